@@ -0,0 +1,52 @@
+package qrstr
+
+import (
+	"html"
+	"image"
+	"strings"
+)
+
+// srOnly hides the text fallback of HTML codes from sight but not from screen readers.
+const srOnly = `position:absolute;width:1px;height:1px;margin:-1px;padding:0;border:0;overflow:hidden;clip:rect(0,0,0,0);white-space:nowrap;`
+
+// WithAltText sets the text screen readers announce for SVG and HTML mode codes, as the
+// aria-label or alt of the code. By default it is the headers, or the payload if there are
+// none, or "QR code" for payloads of WithSensitive.
+func WithAltText(s string) Option {
+	return func(q *Encoder) {
+		q.alt = s
+	}
+}
+
+// altText returns the accessible name of code shown with headers.
+func (q *Encoder) altText(code *image.Image, headers *[]string) string {
+	switch {
+	case q.alt != "":
+		return q.alt
+	case headers != nil && len(*headers) > 0:
+		return strings.Join(*headers, " ")
+	}
+	if l, ok := (*code).(labeled); ok && l.payload != "" && !q.sensitive {
+		return l.payload
+	}
+	return "QR code"
+}
+
+// fallback returns the visually hidden text that stands in for an HTML code when its
+// graphic cannot be read out, alt unless it is already shown as the headers.
+func (q *Encoder) fallback(alt string, headers *[]string, shared bool) string {
+	if headers != nil {
+		shown := strings.Join(*headers, " ")
+		if q.rawHeaders && !q.xml {
+			// markup headers read out as their text
+			shown = html.UnescapeString(shown)
+		}
+		if alt == shown {
+			return ""
+		}
+	}
+	if q.nonce != "" || shared {
+		return `<span class="` + q.prefix() + `-sr">` + html.EscapeString(alt) + "</span>"
+	}
+	return `<span style="` + srOnly + `">` + html.EscapeString(alt) + "</span>"
+}
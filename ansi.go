@@ -0,0 +1,141 @@
+package qrstr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// cp437 holds the characters of code page 437 from 0x80 to 0xff.
+const cp437 = "ÇüéâäàåçêëèïîìÄÅ" +
+	"ÉæÆôöòûùÿÖÜ¢£¥₧ƒ" +
+	"áíóúñÑªº¿⌐¬½¼¡«»" +
+	"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" +
+	"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" +
+	"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" +
+	"αßΓπΣσµτΦΘΩδ∞φε∩" +
+	"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■ "
+
+// toCP437 converts s to code page 437 bytes, characters it does not have become '?'.
+func toCP437(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r >= ' ' && r <= '~' {
+			b = append(b, byte(r))
+			continue
+		}
+		c := byte('?')
+		i := 0
+		for _, v := range cp437 {
+			if v == r {
+				c = byte(0x80 + i)
+				break
+			}
+			i++
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// fromCP437 converts code page 437 bytes to UTF-8, control characters are kept as they are.
+func fromCP437(s string) string {
+	high := []rune(cp437)
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x80 {
+			b.WriteByte(s[i])
+		} else {
+			b.WriteRune(high[s[i]-0x80])
+		}
+	}
+	return b.String()
+}
+
+// SAUCE is the metadata record appended to ANSI art files.
+// Strings are converted to CP437 and cut to their field length.
+type SAUCE struct {
+	Title  string // up to 35 characters
+	Author string // up to 20 characters
+	Group  string // up to 20 characters
+	// Date is the creation date, the current date is used if it is zero,
+	// or the date of WithReproducible.
+	Date time.Time
+}
+
+// WithSAUCE appends a SAUCE record with the given metadata to ANSIMode output.
+func WithSAUCE(s SAUCE) Option {
+	return func(q *Encoder) {
+		q.sauce = &s
+	}
+}
+
+// record returns the 128 byte SAUCE record for an ANSI file of size bytes, width columns and height lines,
+// made at now.
+func (s *SAUCE) record(now time.Time, size, width, height int) []byte {
+	field := func(v string, n int) []byte {
+		b := toCP437(v)
+		if len(b) > n {
+			b = b[:n]
+		}
+		return append(b, bytes.Repeat([]byte{' '}, n-len(b))...)
+	}
+	date := s.Date
+	if date.IsZero() {
+		date = now
+	}
+	r := []byte("SAUCE00")
+	r = append(r, field(s.Title, 35)...)
+	r = append(r, field(s.Author, 20)...)
+	r = append(r, field(s.Group, 20)...)
+	r = append(r, date.Format("20060102")...)
+	r = binary.LittleEndian.AppendUint32(r, uint32(size))
+	// data type 1 is character, file type 1 is ANSi
+	r = append(r, 1, 1)
+	r = binary.LittleEndian.AppendUint16(r, uint16(width))
+	r = binary.LittleEndian.AppendUint16(r, uint16(height))
+	r = append(r, 0, 0, 0, 0)
+	// no comments, no flags, then the font name
+	r = append(r, 0, 0)
+	return append(r, append([]byte("IBM VGA"), make([]byte, 22-7)...)...)
+}
+
+func (q *Encoder) ansi(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	lines, err := q.textRows(rc, code, headers)
+	if err != nil {
+		return "", err
+	}
+	eol := q.lineEnd("\r\n")
+	var b bytes.Buffer
+	for _, l := range lines {
+		// bright white on black, the same colours as TerminalMode
+		b.WriteString("\033[0;1;37;40m")
+		b.Write(toCP437(l))
+		b.WriteString("\033[0m" + eol)
+	}
+	if q.sauce != nil {
+		size := b.Len()
+		b.WriteByte(0x1a)
+		b.Write(q.sauce.record(q.now(), size, utf8.RuneCountInString(lines[0]), len(lines)))
+	}
+	return b.String(), nil
+}
+
+// cp437 draws the code like TextDarkMode as raw code page 437 bytes with CRLF line ends,
+// unless WithLineEnding sets others.
+func (q *Encoder) cp437(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	lines, err := q.textRows(rc, code, headers)
+	if err != nil {
+		return "", err
+	}
+	eol := q.lineEnd("\r\n")
+	var b bytes.Buffer
+	for _, l := range lines {
+		b.Write(toCP437(l))
+		b.WriteString(eol)
+	}
+	return b.String(), nil
+}
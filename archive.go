@@ -0,0 +1,91 @@
+package qrstr
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"io"
+	"time"
+)
+
+// ArchiveName is the default file name template of WriteBatch and the archive writers.
+const ArchiveName = "{{.Index}}{{.Ext}}"
+
+// ext returns the file extension for output of the encoder type.
+func (t EncoderType) ext() string {
+	switch t {
+	case HTMLMode, HTMLGridMode, HTMLImageMode, HTMLSVGMode, HTMLFragmentMode, HTMLCanvasMode, HTMLTableMode:
+		return ".html"
+	case SVGMode:
+		return ".svg"
+	case ANSIMode:
+		return ".ans"
+	case SixelMode:
+		return ".six"
+	case EPSMode:
+		return ".eps"
+	case ZPLMode:
+		return ".zpl"
+	case ESCPOSMode:
+		return ".bin"
+	case TSPLMode, EPLMode:
+		return ".prn"
+	case PBMMode:
+		return ".pbm"
+	case TikZMode:
+		return ".tex"
+	}
+	return ".txt"
+}
+
+// zipSink puts files in a zip archive.
+type zipSink struct {
+	w   *zip.Writer
+	mod time.Time
+}
+
+func (z zipSink) Put(name string, r io.Reader) error {
+	f, err := z.w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: z.mod})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// tarSink puts files in a tar archive, reading each one into memory for its size.
+type tarSink struct {
+	w   *tar.Writer
+	mod time.Time
+}
+
+func (t tarSink) Put(name string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	err = t.w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(b)), ModTime: t.mod, Typeflag: tar.TypeReg})
+	if err != nil {
+		return err
+	}
+	_, err = t.w.Write(b)
+	return err
+}
+
+// WriteZip renders every item and writes it to a zip archive on w, one file per item
+// named like WriteBatch names them.
+func (q *Encoder) WriteZip(w io.Writer, items []BatchItem, name string) error {
+	z := zip.NewWriter(w)
+	if err := q.WriteBatch(zipSink{z, q.now()}, items, name); err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// WriteTar renders every item and writes it to a tar archive on w, named like WriteBatch.
+func (q *Encoder) WriteTar(w io.Writer, items []BatchItem, name string) error {
+	t := tar.NewWriter(w)
+	if err := q.WriteBatch(tarSink{t, q.now()}, items, name); err != nil {
+		return err
+	}
+	return t.Close()
+}
@@ -0,0 +1,124 @@
+package qrstr
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BatchItem is one code of a batch run.
+type BatchItem struct {
+	Payload string
+	// Headers are displayed above the code, like the headers of Encode.
+	Headers []string
+	// Output is set by EncodeBatch to the rendered code.
+	Output string
+	// File is set by WriteBatch to the name the code was stored as.
+	File string
+	// Result is set by EncodeBatch and WriteBatch to the code with its metadata.
+	Result *Result
+}
+
+// EncodeBatch renders every item and stores the result in its Output.
+// Items with the same payload and headers are rendered once and share their Result.
+// It stops at the first item that fails to encode.
+func (q *Encoder) EncodeBatch(items []BatchItem) error {
+	var seen batchCache
+	for i := range items {
+		r, err := seen.encode(q, items[i])
+		if err != nil {
+			return err
+		}
+		items[i].Output = r.Output
+		items[i].Result = r
+	}
+	return nil
+}
+
+// batchCache holds the results of a batch run by payload and headers, for runs that repeat
+// the same URL many times. The zero value is ready to use.
+type batchCache map[string]*Result
+
+// encode returns the result of an earlier item with the payload and headers of v,
+// or encodes v and keeps its result.
+func (c *batchCache) encode(q *Encoder, v BatchItem) (*Result, error) {
+	key := strconv.Quote(v.Payload)
+	for _, h := range v.Headers {
+		key += " " + strconv.Quote(h)
+	}
+	if r, ok := (*c)[key]; ok {
+		return r, nil
+	}
+	r, err := q.EncodeResult(v.Payload, v.Headers...)
+	if err != nil {
+		return nil, err
+	}
+	if *c == nil {
+		*c = make(batchCache)
+	}
+	(*c)[key] = r
+	return r, nil
+}
+
+// ManifestEntry describes one code of a batch run in a manifest.
+type ManifestEntry struct {
+	Index   int    `json:"index"`
+	Payload string `json:"payload"`
+	Caption string `json:"caption"`
+	File    string `json:"file,omitempty"`
+	// Version, ErrorCorrection, Size and Fingerprint are left empty for items that were not encoded.
+	Version         int    `json:"version,omitempty"`
+	ErrorCorrection string `json:"ecl,omitempty"`
+	// Size is the width and height of the code in modules, without the quiet zone.
+	Size        int    `json:"size,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Manifest returns a manifest entry for each item. Items encoded WithSensitive list a hash of
+// their payload, see Redacted.
+func Manifest(items []BatchItem) []ManifestEntry {
+	m := make([]ManifestEntry, len(items))
+	for i, v := range items {
+		m[i] = ManifestEntry{Index: i + 1, Payload: v.Payload, Caption: strings.Join(v.Headers, " "), File: v.File}
+		if r := v.Result; r != nil {
+			if r.sensitive {
+				m[i].Payload = r.enc.Redacted(v.Payload)
+			}
+			m[i].Version = r.Version
+			m[i].ErrorCorrection = r.ErrorCorrection.String()
+			m[i].Size = r.Size
+			m[i].Fingerprint = r.Fingerprint()
+		}
+	}
+	return m
+}
+
+// WriteManifest writes a CSV listing the index, payload, headers, file name, qr version,
+// error correction level, size and fingerprint of each item,
+// so a printing run can be checked against what was generated.
+func WriteManifest(w io.Writer, items []BatchItem) error {
+	c := csv.NewWriter(w)
+	if err := c.Write([]string{"index", "payload", "caption", "file", "version", "ecl", "size", "fingerprint"}); err != nil {
+		return err
+	}
+	for _, v := range Manifest(items) {
+		version, size := "", ""
+		if v.Version > 0 {
+			version, size = strconv.Itoa(v.Version), strconv.Itoa(v.Size)
+		}
+		if err := c.Write([]string{strconv.Itoa(v.Index), v.Payload, v.Caption, v.File, version, v.ErrorCorrection, size, v.Fingerprint}); err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// WriteManifestJSON writes the manifest of WriteManifest as a JSON array of ManifestEntry.
+func WriteManifestJSON(w io.Writer, items []BatchItem) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "\t")
+	return e.Encode(Manifest(items))
+}
@@ -0,0 +1,178 @@
+package qrstr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BCBPLeg is one flight segment of an IATA bar coded boarding pass.
+type BCBPLeg struct {
+	// PNR is the operating carrier booking reference, up to 7 characters.
+	PNR string
+	// From and To are the 3 letter IATA airport codes.
+	From string
+	To   string
+	// Carrier is the 2 or 3 character operating carrier designator.
+	Carrier string
+	// FlightNumber is up to 4 digits, optionally followed by a letter suffix.
+	FlightNumber string
+	// Date is the date of the flight, only the day of the year is encoded.
+	Date time.Time
+	// Compartment is the cabin class letter, like Y or J.
+	Compartment byte
+	// Seat is the seat number, like 12A. Empty for no seat.
+	Seat string
+	// CheckInSeq is the check-in sequence number, up to 4 digits and an optional letter.
+	CheckInSeq string
+	// PassengerStatus is the single character passenger status, defaults to 1 (checked in).
+	PassengerStatus byte
+	// Conditional is appended to the leg as is, it holds any conditional or airline use fields.
+	Conditional string
+}
+
+// BCBP builds the M1 format payload of an IATA bar coded boarding pass.
+// The payload is plain text, so it can be encoded in any symbology that accepts it.
+type BCBP struct {
+	// PassengerName is formatted as LAST/FIRST and truncated to 20 characters.
+	PassengerName string
+	// ETicket marks the boarding pass as an electronic ticket.
+	ETicket bool
+	// Legs holds between 1 and 4 flight segments.
+	Legs []BCBPLeg
+}
+
+var ErrBCBPLegs = fmt.Errorf("boarding pass must have between 1 and 4 legs")
+
+// bcbpField validates s against max length and allowed characters, then pads it to n.
+func bcbpField(name, s string, n int, ok func(r rune) bool) (string, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) > n {
+		return "", fmt.Errorf("boarding pass %s is longer than %d characters: %q", name, n, s)
+	}
+	for _, r := range s {
+		if !ok(r) {
+			return "", fmt.Errorf("boarding pass %s has invalid character %q", name, r)
+		}
+	}
+	return s + pad(n-len(s), blank), nil
+}
+
+func bcbpAlpha(r rune) bool { return r >= 'A' && r <= 'Z' }
+func bcbpDigit(r rune) bool { return r >= '0' && r <= '9' }
+func bcbpAlnum(r rune) bool { return bcbpAlpha(r) || bcbpDigit(r) }
+func bcbpPrint(r rune) bool { return r >= ' ' && r <= '~' }
+
+// bcbpNumber formats a number with an optional letter suffix as n zero padded digits and one suffix character.
+func bcbpNumber(name, s string, n int) (string, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	suffix := " "
+	if len(s) > 0 && bcbpAlpha(rune(s[len(s)-1])) {
+		suffix = s[len(s)-1:]
+		s = s[:len(s)-1]
+	}
+	if len(s) == 0 || len(s) > n {
+		return "", fmt.Errorf("boarding pass %s must have between 1 and %d digits: %q", name, n, s)
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return "", fmt.Errorf("boarding pass %s is not a number: %q", name, s)
+	}
+	return fmt.Sprintf("%0*d", n, v) + suffix, nil
+}
+
+// Payload formats the leg as its mandatory repeated fields followed by the conditional fields.
+func (l BCBPLeg) Payload() (string, error) {
+	var out string
+	add := func(s string, err error) error {
+		out += s
+		return err
+	}
+	if err := add(bcbpField("PNR", l.PNR, 7, bcbpAlnum)); err != nil {
+		return "", err
+	}
+	for _, v := range []string{l.From, l.To} {
+		if len(strings.TrimSpace(v)) != 3 {
+			return "", fmt.Errorf("boarding pass airport code must be 3 letters: %q", v)
+		}
+		if err := add(bcbpField("airport code", v, 3, bcbpAlpha)); err != nil {
+			return "", err
+		}
+	}
+	if len(strings.TrimSpace(l.Carrier)) < 2 {
+		return "", fmt.Errorf("boarding pass carrier must be 2 or 3 characters: %q", l.Carrier)
+	}
+	if err := add(bcbpField("carrier", l.Carrier, 3, bcbpAlnum)); err != nil {
+		return "", err
+	}
+	if err := add(bcbpNumber("flight number", l.FlightNumber, 4)); err != nil {
+		return "", err
+	}
+	if l.Date.IsZero() {
+		return "", fmt.Errorf("boarding pass flight date is not set")
+	}
+	out += fmt.Sprintf("%03d", l.Date.YearDay())
+	if !bcbpAlpha(rune(l.Compartment)) {
+		return "", fmt.Errorf("boarding pass compartment must be a letter: %q", l.Compartment)
+	}
+	out += string(l.Compartment)
+	if l.Seat == "" {
+		out += pad(4, blank)
+	} else if err := add(bcbpNumber("seat", l.Seat, 3)); err != nil {
+		return "", err
+	}
+	if err := add(bcbpNumber("check-in sequence", l.CheckInSeq, 4)); err != nil {
+		return "", err
+	}
+	status := l.PassengerStatus
+	if status == 0 {
+		status = '1'
+	}
+	if !bcbpPrint(rune(status)) {
+		return "", fmt.Errorf("boarding pass passenger status is invalid: %q", status)
+	}
+	out += string(status)
+	if len(l.Conditional) > 0xff {
+		return "", fmt.Errorf("boarding pass conditional data is longer than 255 characters")
+	}
+	for _, r := range l.Conditional {
+		if !bcbpPrint(r) {
+			return "", fmt.Errorf("boarding pass conditional data has invalid character %q", r)
+		}
+	}
+	out += fmt.Sprintf("%02X", len(l.Conditional)) + l.Conditional
+	return out, nil
+}
+
+// Payload returns the M1 payload for the boarding pass, or an error if any field is invalid.
+func (b BCBP) Payload() (string, error) {
+	if len(b.Legs) < 1 || len(b.Legs) > 4 {
+		return "", ErrBCBPLegs
+	}
+	name := strings.ToUpper(strings.TrimSpace(b.PassengerName))
+	if name == "" {
+		return "", fmt.Errorf("boarding pass passenger name is empty")
+	}
+	if len(name) > 20 {
+		name = name[:20]
+	}
+	name, err := bcbpField("passenger name", name, 20, bcbpPrint)
+	if err != nil {
+		return "", err
+	}
+	out := fmt.Sprintf("M%d%s", len(b.Legs), name)
+	if b.ETicket {
+		out += "E"
+	} else {
+		out += string(blank)
+	}
+	var leg string
+	for _, l := range b.Legs {
+		if leg, err = l.Payload(); err != nil {
+			return "", err
+		}
+		out += leg
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package qrstr
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBCBPPayload(t *testing.T) {
+	// the mandatory fields of the example pass in IATA Resolution 792
+	b := BCBP{
+		PassengerName: "Desmarais/Luc",
+		ETicket:       true,
+		Legs: []BCBPLeg{{
+			PNR:          "ABC123",
+			From:         "YUL",
+			To:           "FRA",
+			Carrier:      "AC",
+			FlightNumber: "834",
+			Date:         time.Date(2026, time.November, 22, 0, 0, 0, 0, time.UTC),
+			Compartment:  'J',
+			Seat:         "1A",
+			CheckInSeq:   "25",
+		}},
+	}
+	got, err := b.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "M1DESMARAIS/LUC       EABC123 YULFRAAC 0834 326J001A0025 100"
+	if got != want {
+		t.Errorf("Payload() =\n%q, want\n%q", got, want)
+	}
+	if len(got) != 60 {
+		t.Errorf("Payload() is %d characters, the mandatory fields of one leg are 60", len(got))
+	}
+}
+
+func TestBCBPPayloadErrors(t *testing.T) {
+	leg := BCBPLeg{PNR: "ABC123", From: "YUL", To: "FRA", Carrier: "AC", FlightNumber: "834",
+		Date: time.Date(2026, time.November, 22, 0, 0, 0, 0, time.UTC), Compartment: 'J', CheckInSeq: "25"}
+	if _, err := (BCBP{PassengerName: "A/B"}).Payload(); !errors.Is(err, ErrBCBPLegs) {
+		t.Errorf("no legs: got %v, want ErrBCBPLegs", err)
+	}
+	bad := []func(l *BCBPLeg){
+		func(l *BCBPLeg) { l.PNR = "ABCDEFGH" },
+		func(l *BCBPLeg) { l.From = "YU" },
+		func(l *BCBPLeg) { l.Carrier = "A" },
+		func(l *BCBPLeg) { l.FlightNumber = "12345" },
+		func(l *BCBPLeg) { l.Date = time.Time{} },
+		func(l *BCBPLeg) { l.Compartment = '1' },
+	}
+	for i, f := range bad {
+		l := leg
+		f(&l)
+		if _, err := (BCBP{PassengerName: "A/B", Legs: []BCBPLeg{l}}).Payload(); err == nil {
+			t.Errorf("invalid leg %d was accepted", i)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package qrstr
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// termColor and termReset are the escape codes TerminalMode wraps each line in by default.
+const termColor = "\033[40;97m"
+const termReset = "\033[0m"
+
+// ColorDepth selects the colour escapes TerminalMode uses for the colours of WithColors.
+type ColorDepth int
+
+const (
+	// Color16 uses the 16 basic colours every terminal has, the nearest to each colour. Default.
+	Color16 ColorDepth = 0
+	// Color256 uses the nearest of the xterm 256 colour palette.
+	Color256 ColorDepth = 1
+	// TrueColor uses 24 bit colours as they are, for terminals that set COLORTERM=truecolor.
+	TrueColor ColorDepth = 2
+)
+
+// String returns the name of the colour depth, like 256.
+func (d ColorDepth) String() string {
+	switch d {
+	case Color16:
+		return "16"
+	case Color256:
+		return "256"
+	case TrueColor:
+		return "truecolor"
+	}
+	return fmt.Sprintf("ColorDepth(%d)", int(d))
+}
+
+// WithColorDepth sets the colour escapes TerminalMode draws the colours of WithColors with.
+// Terminal themes can change the 16 basic colours, the other depths look the same everywhere.
+func WithColorDepth(d ColorDepth) Option {
+	return func(q *Encoder) {
+		q.depth = d
+	}
+}
+
+// ansi16 holds the 16 basic colours as xterm draws them, the bright ones last.
+var ansi16 = color.Palette{
+	color.RGBA{0, 0, 0, 255}, color.RGBA{205, 0, 0, 255}, color.RGBA{0, 205, 0, 255}, color.RGBA{205, 205, 0, 255},
+	color.RGBA{0, 0, 238, 255}, color.RGBA{205, 0, 205, 255}, color.RGBA{0, 205, 205, 255}, color.RGBA{229, 229, 229, 255},
+	color.RGBA{127, 127, 127, 255}, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{255, 255, 0, 255},
+	color.RGBA{92, 92, 255, 255}, color.RGBA{255, 0, 255, 255}, color.RGBA{0, 255, 255, 255}, color.RGBA{255, 255, 255, 255},
+}
+
+// sgr returns the parameters setting c as the foreground colour at depth d, or the background if bg is set.
+// Transparent colours leave the terminal default.
+func (d ColorDepth) sgr(c color.Color, bg bool) string {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		if bg {
+			return "49"
+		}
+		return "39"
+	}
+	base := 30
+	if bg {
+		base = 40
+	}
+	switch d {
+	case TrueColor:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r>>8, g>>8, b>>8)
+	case Color256:
+		// the 6x6x6 cube from 16 and the grey ramp from 232, whichever is nearer
+		level := func(v uint32) int { return (int(v>>8)*5 + 127) / 255 }
+		cube := 16 + 36*level(r) + 6*level(g) + level(b)
+		grey := 232 + min(23, max(0, (int(luma(c)*255)-3)/10))
+		p := color.Palette{xterm256(cube), xterm256(grey)}
+		n := cube
+		if p.Index(c) == 1 {
+			n = grey
+		}
+		return fmt.Sprintf("%d;5;%d", base+8, n)
+	}
+	i := ansi16.Index(c)
+	if i >= 8 {
+		return fmt.Sprint(base + 60 + i - 8)
+	}
+	return fmt.Sprint(base + i)
+}
+
+// xterm256 returns colour n of the xterm palette from the cube or the grey ramp.
+func xterm256(n int) color.Color {
+	if n >= 232 {
+		v := uint8(8 + 10*(n-232))
+		return color.RGBA{v, v, v, 255}
+	}
+	n -= 16
+	step := func(i int) uint8 {
+		if i == 0 {
+			return 0
+		}
+		return uint8(55 + 40*i)
+	}
+	return color.RGBA{step(n / 36), step(n / 6 % 6), step(n % 6), 255}
+}
+
+// termEscape returns the escape TerminalMode starts each line with. Light modules are drawn in
+// the terminal foreground, so they take the light colour and the background takes the dark one.
+func (q *Encoder) termEscape() string {
+	if q.fg == nil && q.bg == nil {
+		return termColor
+	}
+	fg, bg := q.palette()
+	if q.inverted {
+		fg, bg = bg, fg
+	}
+	return "\033[" + q.depth.sgr(fg, true) + ";" + q.depth.sgr(bg, false) + "m"
+}
+
+// WithForceColor keeps the colour escapes of TerminalMode when standard output is not a
+// terminal, for output that is shown on a terminal later or elsewhere.
+func WithForceColor() Option {
+	return func(q *Encoder) {
+		q.forceColor = true
+	}
+}
+
+// WithNoColor leaves out the colour escapes of TerminalMode even on a terminal.
+func WithNoColor() Option {
+	return func(q *Encoder) {
+		q.noColor = true
+	}
+}
+
+// WithResetOnce sets the colours of TerminalMode once before the code and resets them after it,
+// instead of on every line, for status bars and panes that style the lines around it.
+// Terminals may paint the background colour past the right edge of the code when they
+// scroll, WithNoBleed stops that.
+func WithResetOnce() Option {
+	return func(q *Encoder) {
+		q.resetOnce = true
+	}
+}
+
+// WithNoBleed ends each line of WithResetOnce output with the default background and sets
+// the colours again on the next, so no background colour spills past the code's right edge.
+func WithNoBleed() Option {
+	return func(q *Encoder) {
+		q.noBleed = true
+	}
+}
+
+// termLine returns a line of TerminalMode output with its escapes, first and last mark the
+// first and last lines of the code.
+func (q *Encoder) termLine(l string, first, last bool) string {
+	if !q.resetOnce {
+		return q.termEscape() + l + termReset
+	}
+	if first || q.noBleed {
+		l = q.termEscape() + l
+	}
+	switch {
+	case last:
+		return l + termReset
+	case q.noBleed:
+		return l + "\033[49m"
+	}
+	return l
+}
+
+// ParseColors returns the option for a colour setting as used by QRSTR_COLORS: always for
+// WithForceColor, never for WithNoColor, or auto or empty to check standard output.
+func ParseColors(s string) (Option, error) {
+	switch strings.ToLower(s) {
+	case "always":
+		return WithForceColor(), nil
+	case "never":
+		return WithNoColor(), nil
+	case "auto", "":
+		return func(q *Encoder) {}, nil
+	}
+	return nil, fmt.Errorf("invalid colour setting: %q", s)
+}
+
+// escapes reports whether TerminalMode output gets colour escapes.
+func (q *Encoder) escapes() bool {
+	if q.noColor {
+		return false
+	}
+	return q.forceColor || isTerminal()
+}
+
+// Block is a rendered code measured for placing inside other terminal layouts,
+// such as lipgloss styles or Bubble Tea views.
+type Block struct {
+	// Lines holds each row of the code. In TerminalMode every line sets its own
+	// colours and resets them at its end, so lines can be rearranged freely.
+	Lines []string
+	// Width is the number of terminal columns of the widest line.
+	Width int
+	// Height is the number of lines.
+	Height int
+}
+
+// String joins the lines with newlines, without a trailing newline.
+func (b Block) String() string {
+	return strings.Join(b.Lines, "\n")
+}
+
+// EncodeBlock renders data as a Block. Text modes produce plain lines, TerminalMode
+// adds its colours to each line, even with WithResetOnce, other modes use the light mode characters.
+func (q *Encoder) EncodeBlock(data string, headers ...string) (Block, error) {
+	var b Block
+	var err error
+	if b.Lines, err = q.lines(data, headers); err != nil {
+		return b, err
+	}
+	b.Height = len(b.Lines)
+	for i, l := range b.Lines {
+		b.Width = max(b.Width, DisplayWidth(l))
+		if q.mode == TerminalMode && q.escapes() {
+			b.Lines[i] = q.termEscape() + l + termReset
+		}
+	}
+	return b, nil
+}
+
+// textRC returns the characters used for text renderings, non-text modes use light mode.
+func (q *Encoder) textRC() *runeCol {
+	if q.rc == nil {
+		return &lightMode
+	}
+	return q.rc
+}
+
+// lines renders data with the text renderer and returns its lines without newlines.
+func (q *Encoder) lines(data string, headers []string) ([]string, error) {
+	code, err := q.code(data)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	headers = q.prepare(code, headers)
+	err = q.textLines(q.textRC(), &code, &headers, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	return lines, err
+}
@@ -0,0 +1,58 @@
+package qrstr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+)
+
+// canvasScript draws the canvas before it from its data-qr attribute, the modules of the image
+// packed eight to a byte from the top left, formatted with the scale and the light and dark colours.
+const canvasScript = `(function(c){var s=atob(c.dataset.qr),w=+c.dataset.width,h=+c.dataset.height,x=c.getContext("2d");` +
+	`x.fillStyle=%[2]q;x.fillRect(0,0,w*%[1]d,h*%[1]d);x.fillStyle=%[3]q;` +
+	`for(var i=0;i<w*h;i++)if(s.charCodeAt(i>>3)>>(7-(i&7))&1)x.fillRect(i%%w*%[1]d,(i/w|0)*%[1]d,%[1]d,%[1]d)` +
+	`})(document.currentScript.previousElementSibling)`
+
+// canvas draws the code as a <canvas> with the modules packed in an attribute and a script
+// that paints them, the headers as its label and fallback content.
+func (q *Encoder) canvas(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	img := q.image(code).(*image.Paletted)
+	b := img.Bounds()
+	bits := make([]byte, (b.Dx()*b.Dy()+7)/8)
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.ColorIndexAt(x, y) == 1 {
+				bits[i/8] |= 0x80 >> (i % 8)
+			}
+			i++
+		}
+	}
+	alt := q.altText(code, headers)
+	nonce := ""
+	if q.nonce != "" {
+		nonce = ` nonce="` + html.EscapeString(q.nonce) + `"`
+	}
+	fg, bg := q.palette()
+	script := fmt.Sprintf(canvasScript, scale, cssColor(bg), cssColor(fg))
+	if q.xml {
+		// the script has < and & in it, which XML only takes in CDATA
+		script = "//<![CDATA[\n" + script + "\n//]]>"
+	}
+	s := fmt.Sprintf(`<canvas id="%s" width="%d" height="%d" role="img" aria-label="%s" data-qr="%s" data-width="%d" data-height="%d">%s</canvas><script%s>%s</script>`,
+		q.codeID(*code, deref(headers)), b.Dx()*scale, b.Dy()*scale, html.EscapeString(alt),
+		base64.StdEncoding.EncodeToString(bits), b.Dx(), b.Dy(), html.EscapeString(alt), nonce, script)
+	if q.xml {
+		// one root element, so the output is an XML document
+		s = `<div xmlns="http://www.w3.org/1999/xhtml">` + s + "</div>"
+	}
+	return s, nil
+}
@@ -0,0 +1,35 @@
+package qrstr
+
+import (
+	"image/color"
+)
+
+// Cell is one character cell of a code drawn in a text user interface.
+// Fg and Bg convert to tcell colours with tcell.FromImageColor, and a row of cells
+// maps directly onto calls to tcell's Screen.SetContent.
+type Cell struct {
+	Rune rune
+	Fg   color.Color
+	Bg   color.Color
+}
+
+// EncodeCells renders data as rows of cells instead of a string, so TUI applications
+// can draw the code into their own views. Text and terminal modes keep their own
+// characters and colours, other modes use the light mode characters.
+func (q *Encoder) EncodeCells(data string, headers ...string) ([][]Cell, error) {
+	lines, err := q.lines(data, headers)
+	if err != nil {
+		return nil, err
+	}
+	var fg, bg color.Color = color.Black, color.White
+	if q.textRC().inverted() {
+		fg, bg = bg, fg
+	}
+	cells := make([][]Cell, len(lines))
+	for y, l := range lines {
+		for _, r := range l {
+			cells[y] = append(cells[y], Cell{Rune: r, Fg: fg, Bg: bg})
+		}
+	}
+	return cells, nil
+}
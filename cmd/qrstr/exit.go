@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"git.sophuwu.com/qrstr"
+)
+
+// Exit codes, so scripts can tell why qrstr failed.
+const (
+	exitFailure  = 1 // anything else
+	exitUsage    = 2 // invalid flags or option values, as the flag package exits with
+	exitCapacity = 3 // the payload, headers or overlay do not fit in a qr code
+	exitIO       = 4 // reading the payload or writing the code failed
+)
+
+// exitError is an error with the exit code and kind it is reported with, and the usage
+// printed after it for invalid flags.
+type exitError struct {
+	code  int
+	kind  string
+	err   error
+	usage func()
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// usageError marks err as caused by invalid flags or option values.
+func usageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{exitUsage, "usage", err, nil}
+}
+
+// ioError marks err as a failure to read input or write output.
+func ioError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{exitIO, "io", err, nil}
+}
+
+// ioWriter marks the errors of writing to w as io errors.
+type ioWriter struct{ w io.Writer }
+
+func (w ioWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	return n, ioError(err)
+}
+
+// classify returns the exit code and kind of err.
+func classify(err error) (int, string) {
+	var e *exitError
+	switch {
+	case errors.As(err, &e):
+		return e.code, e.kind
+	case errors.Is(err, qrstr.ErrDataTooLong), errors.Is(err, qrstr.ErrHeaderTooLong),
+		errors.Is(err, qrstr.ErrOverlayTooBig), errors.Is(err, qrstr.ErrTooManySkipped):
+		return exitCapacity, "capacity"
+	case errors.Is(err, qrstr.ErrHeadersNotSupported):
+		return exitUsage, "usage"
+	}
+	return exitFailure, "error"
+}
+
+// jsonErrors is set by -json-errors to report errors as JSON objects.
+var jsonErrors bool
+
+// exit reports err on standard error and exits with its code.
+func exit(err error) {
+	code, kind := classify(err)
+	if jsonErrors {
+		json.NewEncoder(os.Stderr).Encode(struct {
+			Error string `json:"error"`
+			Kind  string `json:"kind"`
+			Code  int    `json:"code"`
+		}{err.Error(), kind, code})
+	} else {
+		fmt.Fprintln(os.Stderr, "qrstr:", err)
+		if e := (*exitError)(nil); errors.As(err, &e) && e.usage != nil {
+			e.usage()
+		}
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,186 @@
+// Command qrstr prints qr codes in the terminal, or serves them over HTTP.
+//
+// Usage:
+//
+//	qrstr [flags] [text ...]
+//	qrstr -watch file [flags]
+//	qrstr serve [-addr :8080]
+//
+// Without text the payload is read from standard input.
+// The defaults of -mode, -ecl and -color are taken from QRSTR_MODE, QRSTR_ECL and QRSTR_COLORS if set.
+// With -watch the code is redrawn in place whenever the file changes,
+// or for every line read from standard input if the file is "-".
+//
+// qrstr exits with 2 for invalid flags, 3 if the payload does not fit in a qr code and 4 if
+// reading or writing fails, or 1 for anything else. With -json-errors the error is written to
+// standard error as a JSON object with its message, kind and exit code.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"git.sophuwu.com/qrstr"
+)
+
+// env returns the environment variable key, or def if it is not set.
+func env(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// headerFlags collects repeated -header flags.
+type headerFlags []string
+
+func (h *headerFlags) String() string     { return strings.Join(*h, ", ") }
+func (h *headerFlags) Set(v string) error { *h = append(*h, v); return nil }
+
+func main() {
+	var err error
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		err = serve(os.Args[2:])
+	} else {
+		err = encode(os.Args[1:])
+	}
+	if err != nil {
+		exit(err)
+	}
+}
+
+func encode(args []string) error {
+	fs := flag.NewFlagSet("qrstr", flag.ContinueOnError)
+	mode := fs.String("mode", env("QRSTR_MODE", "terminal"), "output mode: terminal, dark, light, braille, sixel, kitty, iterm, cp437, eps, zpl, tspl, epl, escpos, pbm, tikz, html, html-grid, html-img, html-svg, html-fragment, html-canvas, html-table, svg or ansi")
+	ecl := fs.String("ecl", env("QRSTR_ECL", "M"), "error correction level: L, M, Q or H")
+	var headers headerFlags
+	fs.Var(&headers, "header", "text displayed above the code, may be repeated")
+	watchPath := fs.String("watch", "", "redraw the code whenever this file changes, - for each line of standard input")
+	color := fs.String("color", env("QRSTR_COLORS", "auto"), "terminal colours: auto, always or never")
+	jsonRPC := fs.Bool("json-rpc", false, "answer JSON-RPC 2.0 generate requests, one per line, on standard input or -socket")
+	socket := fs.String("socket", "", "unix socket to listen on for -json-rpc")
+	goName := fs.String("go", "", "write Go source declaring the code under this name, for go generate")
+	goPkg := fs.String("package", "main", "package of the Go source of -go")
+	matrix := fs.Bool("matrix", false, "declare the -go code as a [][]bool of its modules instead of a string")
+	scheme := fs.String("scheme", "", "characters of text modes: "+strings.Join(qrstr.Schemes(), ", ")+", default from the mode")
+	eol := fs.String("eol", "", "line endings of text modes: lf or crlf, default from the mode")
+	bom := fs.Bool("bom", false, "start text, HTML and SVG output with a UTF-8 byte order mark")
+	metaCharset := fs.Bool("meta-charset", false, "start HTML output with a <meta charset> declaration")
+	document := fs.Bool("document", false, "wrap HTML output in a complete document")
+	title := fs.String("title", "", "title of the -document, default from the headers or payload")
+	fs.BoolVar(&jsonErrors, "json-errors", false, "write errors to standard error as JSON objects")
+	// errors are reported by exit, so -json-errors output stays one JSON object
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		fs.SetOutput(os.Stderr)
+		if errors.Is(err, flag.ErrHelp) {
+			fs.Usage()
+			return nil
+		}
+		return &exitError{exitUsage, "usage", err, fs.Usage}
+	}
+
+	if *jsonRPC {
+		return serveRPC(*socket)
+	}
+	m, err := qrstr.ParseEncoderType(*mode)
+	if err != nil {
+		return usageError(err)
+	}
+	e, err := qrstr.ParseErrorCorrectionLevel(*ecl)
+	if err != nil {
+		return usageError(err)
+	}
+	colors, err := qrstr.ParseColors(*color)
+	if err != nil {
+		return usageError(err)
+	}
+	opts := []qrstr.Option{colors}
+	if *scheme != "" {
+		s, ok := qrstr.LookupScheme(*scheme)
+		if !ok {
+			return usageError(fmt.Errorf("unknown scheme: %q", *scheme))
+		}
+		opts = append(opts, qrstr.WithScheme(s))
+	}
+	if *bom {
+		opts = append(opts, qrstr.WithBOM())
+	}
+	if *document {
+		opts = append(opts, qrstr.WithDocument(*title))
+	}
+	if *metaCharset {
+		opts = append(opts, qrstr.WithMetaCharset())
+	}
+	switch strings.ToLower(*eol) {
+	case "":
+	case "lf":
+		opts = append(opts, qrstr.WithLineEnding(qrstr.LF))
+	case "crlf":
+		opts = append(opts, qrstr.WithLineEnding(qrstr.CRLF))
+	default:
+		return usageError(fmt.Errorf("unknown line ending: %q", *eol))
+	}
+	q, err := qrstr.NewEncoder(m, e, opts...)
+	if err != nil {
+		return usageError(err)
+	}
+	if *watchPath != "" {
+		return watch(q, *watchPath, headers)
+	}
+	data := strings.Join(fs.Args(), " ")
+	if fs.NArg() == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return ioError(err)
+		}
+		data = strings.TrimSuffix(string(b), "\n")
+	}
+	if *goName != "" {
+		kind := qrstr.GoString
+		if *matrix {
+			kind = qrstr.GoMatrix
+		}
+		return q.EncodeGo(os.Stdout, *goPkg, *goName, kind, data, headers...)
+	}
+	return q.EncodeTo(ioWriter{os.Stdout}, data, headers...)
+}
+
+func serve(args []string) error {
+	fs := flag.NewFlagSet("qrstr serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	fs.Parse(args)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", &qrstr.Handler{})
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    16 << 10,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(c)
+	}()
+	fmt.Fprintln(os.Stderr, "qrstr: serving on", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+
+	"git.sophuwu.com/qrstr"
+)
+
+// rpcRequest is a JSON-RPC 2.0 request, one per line.
+type rpcRequest struct {
+	Version string                `json:"jsonrpc"`
+	ID      json.RawMessage       `json:"id,omitempty"`
+	Method  string                `json:"method"`
+	Params  qrstr.GenerateRequest `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	Version string                  `json:"jsonrpc"`
+	ID      json.RawMessage         `json:"id"`
+	Result  *qrstr.GenerateResponse `json:"result,omitempty"`
+	Error   *rpcError               `json:"error,omitempty"`
+}
+
+// serveRPC answers JSON-RPC requests on standard input, or on connections to a unix socket
+// at path if it is not empty, until interrupted.
+func serveRPC(path string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if path == "" {
+		return rpc(ctx, os.Stdin, os.Stdout)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer c.Close()
+			rpc(ctx, c, c)
+		}()
+	}
+}
+
+// rpc reads a request from each line of r and writes the response to w as a line.
+// The only method is generate, its params are a qrstr.GenerateRequest and its result a
+// qrstr.GenerateResponse, whose output is base64 in JSON.
+func rpc(ctx context.Context, r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	enc := json.NewEncoder(w)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var req rpcRequest
+		resp := rpcResponse{Version: "2.0"}
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			resp.ID = json.RawMessage("null")
+			resp.Error = &rpcError{-32700, err.Error()}
+		} else {
+			resp.ID = req.ID
+			if resp.ID == nil {
+				resp.ID = json.RawMessage("null")
+			}
+			resp.Result, resp.Error = call(ctx, &req)
+		}
+		// requests without an id are notifications and get no response
+		if req.ID == nil && resp.Error == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// call answers one request. A panic while generating is answered as an internal error,
+// so one bad request doesn't stop the helper for every client.
+func call(ctx context.Context, req *rpcRequest) (res *qrstr.GenerateResponse, rerr *rpcError) {
+	defer func() {
+		if v := recover(); v != nil {
+			res, rerr = nil, &rpcError{-32603, fmt.Sprintf("internal error: %v", v)}
+		}
+	}()
+	if req.Version != "2.0" {
+		return nil, &rpcError{-32600, "jsonrpc must be 2.0"}
+	}
+	if req.Method != "generate" {
+		return nil, &rpcError{-32601, fmt.Sprintf("unknown method: %q", req.Method)}
+	}
+	res, err := qrstr.Generate(ctx, &req.Params)
+	if err != nil {
+		return nil, &rpcError{-32602, err.Error()}
+	}
+	return res, nil
+}
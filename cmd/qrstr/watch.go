@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"git.sophuwu.com/qrstr"
+)
+
+// watch re-renders the code whenever path changes, or for every line of
+// standard input if path is "-", until interrupted. While path is missing
+// the last code stays on screen.
+func watch(q *qrstr.Encoder, path string, headers []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	r := qrstr.NewRedrawer(os.Stdout)
+	show := func(data string) error {
+		return r.Encode(q, strings.TrimSuffix(data, "\n"), headers...)
+	}
+
+	if path == "-" {
+		lines := make(chan string)
+		go func() {
+			sc := bufio.NewScanner(os.Stdin)
+			for sc.Scan() {
+				lines <- sc.Text()
+			}
+			close(lines)
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case l, ok := <-lines:
+				if !ok {
+					return nil
+				}
+				if err := show(l); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var mod time.Time
+	var size int64 = -1
+	tick := time.NewTicker(250 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		// editors that save by replacing the file leave it missing for a moment,
+		// keep the last frame and look again on the next tick
+		fi, err := os.Stat(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil && (!fi.ModTime().Equal(mod) || fi.Size() != size) {
+			b, err := os.ReadFile(path)
+			switch {
+			case os.IsNotExist(err):
+			case err != nil:
+				return err
+			default:
+				mod, size = fi.ModTime(), fi.Size()
+				if err = show(string(b)); err != nil {
+					return err
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick.C:
+		}
+	}
+}
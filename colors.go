@@ -0,0 +1,21 @@
+package qrstr
+
+import "image/color"
+
+// WithColors sets the colours of dark and light modules in SVG, HTML, image and TerminalMode output,
+// black and white by default. TerminalMode draws them at the depth of WithColorDepth.
+// A colour with no alpha makes those modules transparent. Keep dark modules darker than light ones, many readers need the contrast.
+func WithColors(fg, bg color.Color) Option {
+	return func(q *Encoder) {
+		q.fg = fg
+		q.bg = bg
+	}
+}
+
+// WithModuleSize sets the width and height of SVGMode images so each module is px pixels,
+// by default the image fills its container.
+func WithModuleSize(px int) Option {
+	return func(q *Encoder) {
+		q.moduleSize = px
+	}
+}
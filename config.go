@@ -0,0 +1,322 @@
+package qrstr
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// String returns the short name of the encoder type, like terminal or svg.
+func (t EncoderType) String() string {
+	switch t {
+	case TextDarkMode:
+		return "dark"
+	case TextLightMode:
+		return "light"
+	case HTMLMode:
+		return "html"
+	case TerminalMode:
+		return "terminal"
+	case SVGMode:
+		return "svg"
+	case ANSIMode:
+		return "ansi"
+	case HTMLGridMode:
+		return "html-grid"
+	case BrailleMode:
+		return "braille"
+	case SixelMode:
+		return "sixel"
+	case KittyMode:
+		return "kitty"
+	case ITermMode:
+		return "iterm"
+	case CP437Mode:
+		return "cp437"
+	case EPSMode:
+		return "eps"
+	case ZPLMode:
+		return "zpl"
+	case ESCPOSMode:
+		return "escpos"
+	case TSPLMode:
+		return "tspl"
+	case EPLMode:
+		return "epl"
+	case PBMMode:
+		return "pbm"
+	case TikZMode:
+		return "tikz"
+	case HTMLImageMode:
+		return "html-img"
+	case HTMLSVGMode:
+		return "html-svg"
+	case HTMLFragmentMode:
+		return "html-fragment"
+	case HTMLCanvasMode:
+		return "html-canvas"
+	case HTMLTableMode:
+		return "html-table"
+	}
+	return fmt.Sprintf("EncoderType(%d)", int(t))
+}
+
+// encoderTypes lists every encoder type, for looking them up by name.
+var encoderTypes = []EncoderType{TextDarkMode, TextLightMode, HTMLMode, TerminalMode, SVGMode, ANSIMode, HTMLGridMode, BrailleMode, SixelMode, KittyMode, ITermMode, CP437Mode, EPSMode, ZPLMode, ESCPOSMode, TSPLMode, EPLMode, PBMMode, TikZMode, HTMLImageMode, HTMLSVGMode, HTMLFragmentMode, HTMLCanvasMode, HTMLTableMode}
+
+// ParseEncoderType returns the encoder type with the given name, as returned by its String method.
+func ParseEncoderType(s string) (EncoderType, error) {
+	for _, t := range encoderTypes {
+		if strings.EqualFold(s, t.String()) {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid encoder type: %q", s)
+}
+
+// ParseErrorCorrectionLevel reads an error correction level given as L, M, Q or H.
+func ParseErrorCorrectionLevel(s string) (ErrorCorrectionLevel, error) {
+	switch strings.ToUpper(s) {
+	case "L":
+		return ErrorCorrection7Percent, nil
+	case "M":
+		return ErrorCorrection15Percent, nil
+	case "Q":
+		return ErrorCorrection25Percent, nil
+	case "H":
+		return ErrorCorrection30Percent, nil
+	}
+	return 0, fmt.Errorf("invalid error correction level: %q", s)
+}
+
+// String returns the letter of the error correction level: L, M, Q or H.
+func (e ErrorCorrectionLevel) String() string {
+	if e >= 0 && e <= 3 {
+		return string("LMQH"[e])
+	}
+	return fmt.Sprintf("ErrorCorrectionLevel(%d)", int(e))
+}
+
+// Config is the effective configuration of an Encoder, for logging how codes are made.
+type Config struct {
+	Mode            EncoderType
+	ErrorCorrection ErrorCorrectionLevel
+	// Payload is the class payloads are encoded as, auto if each is analyzed.
+	Payload PayloadClass
+	// QuietZone is the blank border in modules that Encode adds around the code.
+	QuietZone int
+	// Fg and Bg are the colours of dark and light modules, nil in text modes without colour
+	// escapes, which show in the colours of the terminal or page.
+	Fg color.Color
+	Bg color.Color
+	// Inverted is true if dark modules are drawn light, see WithInverted.
+	Inverted bool
+	// Renderer names the way modules are drawn.
+	Renderer string
+	// SAUCE is true if ANSIMode output ends with a SAUCE record.
+	SAUCE bool
+	// LineEnding ends the lines of text modes, empty for the default of the mode.
+	LineEnding string
+	// Scale is the pixels per module of WithScale, ModuleSize that of WithModuleSize and
+	// PhysicalSize the millimetres of WithPhysicalSize, 0 for the defaults of the mode.
+	Scale        int
+	ModuleSize   int
+	PhysicalSize float64
+	// Frame and Padding are the characters of WithFrame and WithPadding, 0 for the defaults.
+	Frame   rune
+	Padding rune
+	// Gutter and GutterWidth place headers beside text codes, see WithGutter.
+	Gutter      Gutter
+	GutterWidth int
+	// HeaderPolicy, HeaderMax and Hyphen lay out long headers, see WithHeaderPolicy.
+	HeaderPolicy HeaderPolicy
+	HeaderMax    int
+	Hyphen       string
+	// PlainHeaders, Sanitize, Templates and RawHTMLHeaders are the header options of the same names.
+	PlainHeaders   bool
+	Sanitize       bool
+	Templates      bool
+	RawHTMLHeaders bool
+	// Overlay is the text of WithOverlay, ShortCode and ModuleHook are true if those are set.
+	Overlay    string
+	ShortCode  bool
+	ModuleHook bool
+	// ColorDepth, ForceColor, NoColor, ResetOnce and NoBleed are the TerminalMode colour options.
+	ColorDepth ColorDepth
+	ForceColor bool
+	NoColor    bool
+	ResetOnce  bool
+	NoBleed    bool
+	// Direction and Align are those of WithDirection.
+	Direction Direction
+	Align     Align
+	// IDPrefix and ClassPrefix start the ids and classes of HTML and SVG codes.
+	IDPrefix    string
+	ClassPrefix string
+	// AltText is the text of WithAltText, Nonce is true if WithNonce is set, its value is left out.
+	AltText string
+	Nonce   bool
+	// XML, DarkMode, Document, BOM and MetaCharset are true if the options of the same names are set.
+	XML         bool
+	DarkMode    bool
+	Document    bool
+	BOM         bool
+	MetaCharset bool
+	// Sensitive and Reproducible are true if WithSensitive and WithReproducible are set.
+	Sensitive    bool
+	Reproducible bool
+	// Middleware is the number of middleware functions, see WithMiddleware.
+	Middleware int
+}
+
+// hexColor formats c as #rrggbb, or transparent if it has no alpha.
+func hexColor(c color.Color) string {
+	if c == nil {
+		return "none"
+	}
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return "transparent"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// cssColor formats c for CSS and SVG as #rrggbb, or transparent if it has no alpha.
+func cssColor(c color.Color) string {
+	switch c {
+	case color.Black:
+		return "black"
+	case color.White:
+		return "white"
+	}
+	return hexColor(c)
+}
+
+// String formats the configuration as space separated key=value pairs.
+func (c Config) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "mode=%s ecl=%s payload=%s quiet=%d fg=%s bg=%s inverted=%t renderer=%s sauce=%t eol=%q",
+		c.Mode, c.ErrorCorrection, c.Payload, c.QuietZone, hexColor(c.Fg), hexColor(c.Bg), c.Inverted, c.Renderer, c.SAUCE, c.LineEnding)
+	fmt.Fprintf(&b, " scale=%d module-size=%d mm=%g frame=%q padding=%q gutter=%s gutter-width=%d",
+		c.Scale, c.ModuleSize, c.PhysicalSize, c.Frame, c.Padding, c.Gutter, c.GutterWidth)
+	fmt.Fprintf(&b, " header-policy=%s header-max=%d hyphen=%q plain-headers=%t sanitize=%t templates=%t raw-html-headers=%t",
+		c.HeaderPolicy, c.HeaderMax, c.Hyphen, c.PlainHeaders, c.Sanitize, c.Templates, c.RawHTMLHeaders)
+	fmt.Fprintf(&b, " overlay=%q short-code=%t module-hook=%t depth=%s force-color=%t no-color=%t reset-once=%t no-bleed=%t",
+		c.Overlay, c.ShortCode, c.ModuleHook, c.ColorDepth, c.ForceColor, c.NoColor, c.ResetOnce, c.NoBleed)
+	fmt.Fprintf(&b, " dir=%s align=%s id-prefix=%q class-prefix=%q alt=%q nonce=%t xml=%t dark-mode=%t document=%t bom=%t meta-charset=%t",
+		c.Direction, c.Align, c.IDPrefix, c.ClassPrefix, c.AltText, c.Nonce, c.XML, c.DarkMode, c.Document, c.BOM, c.MetaCharset)
+	fmt.Fprintf(&b, " sensitive=%t reproducible=%t middleware=%d", c.Sensitive, c.Reproducible, c.Middleware)
+	return b.String()
+}
+
+// DebugConfig returns the effective configuration of the encoder.
+func (q *Encoder) DebugConfig() Config {
+	c := Config{
+		Mode:            q.mode,
+		ErrorCorrection: q.errCorr,
+		Payload:         q.class,
+		Inverted:        q.inverted,
+		SAUCE:           q.sauce != nil,
+		LineEnding:      q.eol,
+		Scale:           q.scale,
+		ModuleSize:      q.moduleSize,
+		PhysicalSize:    q.mm,
+		Frame:           q.frame,
+		Padding:         q.padding,
+		Gutter:          q.gutter,
+		GutterWidth:     q.gutterWidth,
+		HeaderPolicy:    q.headerPolicy,
+		HeaderMax:       q.headerMax,
+		Hyphen:          q.hyphen,
+		PlainHeaders:    q.plainHeaders,
+		Sanitize:        q.sanitize != nil,
+		Templates:       q.templates,
+		RawHTMLHeaders:  q.rawHeaders,
+		Overlay:         q.overlay,
+		ShortCode:       q.short != nil,
+		ModuleHook:      q.hooked(),
+		ColorDepth:      q.depth,
+		ForceColor:      q.forceColor,
+		NoColor:         q.noColor,
+		ResetOnce:       q.resetOnce,
+		NoBleed:         q.noBleed,
+		Direction:       q.dir,
+		Align:           q.align,
+		IDPrefix:        q.idPrefix,
+		ClassPrefix:     q.prefix(),
+		AltText:         q.alt,
+		Nonce:           q.nonce != "",
+		XML:             q.xml,
+		DarkMode:        q.darkMode,
+		Document:        q.document != nil,
+		BOM:             q.bom,
+		MetaCharset:     q.metaCharset,
+		Sensitive:       q.sensitive,
+		Reproducible:    q.reproducible,
+		Middleware:      len(q.middleware),
+	}
+	switch {
+	case q.mode == TerminalMode && !q.noColor:
+		// the escapes of termEscape, white modules on black when no colours are set
+		c.Fg, c.Bg = q.palette()
+	case q.mode == ANSIMode:
+		// ANSIMode always draws bright white on black
+		c.Fg, c.Bg = color.Black, color.White
+	case q.rc == nil:
+		c.Fg, c.Bg = q.palette()
+	}
+	switch q.mode {
+	case HTMLMode, SVGMode, HTMLSVGMode:
+		c.Renderer = "svg-path"
+	case HTMLGridMode, HTMLFragmentMode:
+		c.Renderer = "css-grid"
+	case SixelMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "sixel"
+	case KittyMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "kitty-png"
+	case ITermMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "iterm-png"
+	case EPSMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "postscript"
+	case ZPLMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "zpl-boxes"
+	case ESCPOSMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "escpos-raster"
+	case TSPLMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "tspl-bars"
+	case EPLMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "epl-lines"
+	case PBMMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "pbm"
+	case TikZMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "tikz"
+	case HTMLImageMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "data-uri-png"
+	case HTMLCanvasMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "canvas-script"
+	case HTMLTableMode:
+		c.QuietZone = q.quiet
+		c.Renderer = "inline-table"
+	default:
+		c.QuietZone = 1
+		c.Renderer = q.glyphs.String()
+	}
+	return c
+}
+
+// String returns the effective configuration of the encoder, see DebugConfig.
+func (q *Encoder) String() string {
+	return q.DebugConfig().String()
+}
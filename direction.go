@@ -0,0 +1,79 @@
+package qrstr
+
+import "fmt"
+
+// Direction is the text direction of the div around HTML mode codes.
+type Direction int
+
+const (
+	// DirInherit leaves the direction to the page. Default.
+	DirInherit Direction = 0
+	// DirLTR lays the div out left to right.
+	DirLTR Direction = 1
+	// DirRTL lays the div out right to left, for pages in Arabic, Hebrew and Persian.
+	DirRTL Direction = 2
+)
+
+// String returns the value of the dir attribute for the direction, like rtl.
+func (d Direction) String() string {
+	switch d {
+	case DirInherit:
+		return "inherit"
+	case DirLTR:
+		return "ltr"
+	case DirRTL:
+		return "rtl"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// Align places the headers of HTML mode codes along their line.
+type Align int
+
+const (
+	// AlignStart puts headers at the start of the line, the right edge in right to left text. Default.
+	AlignStart Align = 0
+	// AlignCenter centres headers.
+	AlignCenter Align = 1
+	// AlignEnd puts headers at the end of the line.
+	AlignEnd Align = 2
+)
+
+// String returns the CSS text-align value of the alignment, like center.
+func (a Align) String() string {
+	switch a {
+	case AlignStart:
+		return "start"
+	case AlignCenter:
+		return "center"
+	case AlignEnd:
+		return "end"
+	}
+	return fmt.Sprintf("Align(%d)", int(a))
+}
+
+// WithDirection sets the dir attribute of the div around HTML mode codes and the alignment
+// of their headers. Headers get dir="auto", so a Latin URL in a right to left page keeps its
+// order. The modules are never mirrored, whatever the direction of the page.
+func WithDirection(d Direction, align Align) Option {
+	return func(q *Encoder) {
+		q.dir = d
+		q.align = align
+	}
+}
+
+// dirAttr returns the dir attribute of the div around HTML codes, empty to inherit it.
+func (q *Encoder) dirAttr() string {
+	if q.dir == DirLTR || q.dir == DirRTL {
+		return ` dir="` + q.dir.String() + `"`
+	}
+	return ""
+}
+
+// headerOpen returns the opening tag of a header paragraph of HTML codes with the given id.
+func (q *Encoder) headerOpen(id string) string {
+	if q.dir == DirInherit {
+		return `<p id="` + id + `">`
+	}
+	return `<p id="` + id + `" dir="auto">`
+}
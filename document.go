@@ -0,0 +1,40 @@
+package qrstr
+
+import (
+	"fmt"
+	"html"
+	"image"
+)
+
+// printStyle keeps the colours and the whole of each code on paper, as browsers leave out
+// backgrounds when printing by default.
+const printStyle = `@media print{body{margin:0;}.qr{break-inside:avoid;page-break-inside:avoid;-webkit-print-color-adjust:exact;print-color-adjust:exact;}}`
+
+// WithDocument wraps the output of HTML modes in a complete document with a doctype, title,
+// viewport and print style sheet, so it can be saved and opened or printed as it is.
+// An empty title uses the alt text of the code, see WithAltText.
+func WithDocument(title string) Option {
+	return func(q *Encoder) {
+		q.document = &title
+	}
+}
+
+// wrap returns the HTML document of WithDocument around output, the rendering of code.
+func (q *Encoder) wrap(output string, code image.Image, headers []string) string {
+	title := *q.document
+	if title == "" {
+		title = q.altText(&code, &headers)
+	}
+	end := ">"
+	if q.xml {
+		end = " />"
+	}
+	nonce := ""
+	if q.nonce != "" {
+		nonce = ` nonce="` + html.EscapeString(q.nonce) + `"`
+	}
+	return fmt.Sprintf("<!DOCTYPE html>\n<html xmlns=\"http://www.w3.org/1999/xhtml\">\n<head>\n"+
+		"<meta charset=\"utf-8\"%s\n<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\"%s\n"+
+		"<title>%s</title>\n<style%s>%s</style>\n</head>\n<body>\n%s\n</body>\n</html>\n",
+		end, end, html.EscapeString(title), nonce, q.scope(printStyle), output)
+}
@@ -0,0 +1,134 @@
+package qrstr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"html"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+)
+
+// emailCID is the Content-ID of the code image in emails.
+const emailCID = "qr@qrstr"
+
+// Email is a MIME body holding a code, ready to be sent with net/smtp or a mail library.
+type Email struct {
+	// ContentType is the value of the Content-Type header of the message, with its boundary.
+	// The message also needs the header MIME-Version: 1.0.
+	ContentType string
+	Body        []byte
+}
+
+// EncodeEmail makes an email body showing data as a qr code, for delivering tickets and the like.
+// The body is multipart/alternative: a text/plain part with the code drawn in text,
+// and a multipart/related HTML part with the code as an inline PNG image referenced by its Content-ID.
+// Headers are shown above the code in both. The text is drawn with light mode characters,
+// or dark mode ones for TextDarkMode encoders.
+func (q *Encoder) EncodeEmail(data string, headers ...string) (*Email, error) {
+	code, err := q.code(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = q.checkOverlay(code); err != nil {
+		return nil, err
+	}
+	headers = q.prepare(code, headers)
+	rc := &lightMode
+	if q.mode == TextDarkMode {
+		rc = &darkMode
+	}
+	var text strings.Builder
+	err = q.textLines(rc, &code, &headers, func(line string) error {
+		text.WriteString(line + "\r\n")
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var img bytes.Buffer
+	if err = png.Encode(&img, scaleImage(q.image(&code), 8)); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	related := multipart.NewWriter(io.Discard).Boundary()
+	if q.reproducible {
+		// the boundaries only need to be missing from the encoded parts, which a hash is
+		sum := sha256.Sum256([]byte(text.String()))
+		mw.SetBoundary("qrstr-" + hex.EncodeToString(sum[:16]))
+		related = "qrstr-related-" + hex.EncodeToString(sum[:16])
+	}
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	qp.Write([]byte(text.String()))
+	if err = qp.Close(); err != nil {
+		return nil, err
+	}
+
+	part, err = mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/related; boundary=" + related},
+	})
+	if err != nil {
+		return nil, err
+	}
+	rw := multipart.NewWriter(part)
+	rw.SetBoundary(related)
+	part, err = rw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	alt := "QR code"
+	var body strings.Builder
+	body.WriteString("<!DOCTYPE html>\r\n<html><body>\r\n")
+	for _, v := range headers {
+		body.WriteString("<p>" + html.EscapeString(v) + "</p>\r\n")
+	}
+	if len(headers) > 0 {
+		alt = strings.Join(headers, " ")
+	}
+	body.WriteString(`<img src="cid:` + emailCID + `" alt="` + html.EscapeString(alt) + `">` + "\r\n</body></html>\r\n")
+	qp = quotedprintable.NewWriter(part)
+	qp.Write([]byte(body.String()))
+	if err = qp.Close(); err != nil {
+		return nil, err
+	}
+	part, err = rw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"image/png"},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-ID":                {"<" + emailCID + ">"},
+		"Content-Disposition":       {`inline; filename="qr.png"`},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// base64 lines are kept to 76 characters as MIME asks
+	enc := base64.StdEncoding.EncodeToString(img.Bytes())
+	for len(enc) > 76 {
+		io.WriteString(part, enc[:76]+"\r\n")
+		enc = enc[76:]
+	}
+	io.WriteString(part, enc+"\r\n")
+	if err = rw.Close(); err != nil {
+		return nil, err
+	}
+	if err = mw.Close(); err != nil {
+		return nil, err
+	}
+	return &Email{"multipart/alternative; boundary=" + mw.Boundary(), b.Bytes()}, nil
+}
@@ -0,0 +1,32 @@
+package qrstr
+
+import (
+	"fmt"
+	"os"
+)
+
+// NewAutoEncoder returns an encoder for standard output, TerminalMode with 15% error correction,
+// which users can change without changes to the application through environment variables:
+// QRSTR_MODE takes an encoder type name like dark or svg, QRSTR_ECL an error correction level
+// L, M, Q or H, and QRSTR_COLORS always, never or auto for TerminalMode colours.
+// The options are applied after the environment.
+func NewAutoEncoder(opts ...Option) (*Encoder, error) {
+	mode := TerminalMode
+	ecl := ErrorCorrection15Percent
+	var err error
+	if v := os.Getenv("QRSTR_MODE"); v != "" {
+		if mode, err = ParseEncoderType(v); err != nil {
+			return nil, fmt.Errorf("QRSTR_MODE: %w", err)
+		}
+	}
+	if v := os.Getenv("QRSTR_ECL"); v != "" {
+		if ecl, err = ParseErrorCorrectionLevel(v); err != nil {
+			return nil, fmt.Errorf("QRSTR_ECL: %w", err)
+		}
+	}
+	colors, err := ParseColors(os.Getenv("QRSTR_COLORS"))
+	if err != nil {
+		return nil, fmt.Errorf("QRSTR_COLORS: %w", err)
+	}
+	return NewEncoder(mode, ecl, append([]Option{colors}, opts...)...)
+}
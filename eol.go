@@ -0,0 +1,24 @@
+package qrstr
+
+// Line endings for WithLineEnding.
+const (
+	LF   = "\n"
+	CRLF = "\r\n"
+)
+
+// WithLineEnding ends each line of TextDarkMode, TextLightMode, TerminalMode, BrailleMode,
+// ANSIMode and CP437Mode output with eol, such as CRLF for Windows programs, serial devices
+// and printers. By default lines end with LF, or CRLF in ANSIMode and CP437Mode.
+func WithLineEnding(eol string) Option {
+	return func(q *Encoder) {
+		q.eol = eol
+	}
+}
+
+// lineEnd returns the line ending set by WithLineEnding, or def if there is none.
+func (q *Encoder) lineEnd(def string) string {
+	if q.eol == "" {
+		return def
+	}
+	return q.eol
+}
@@ -0,0 +1,42 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// eps draws the code as an Encapsulated PostScript file with the layout of EncodePDF.
+func (q *Encoder) eps(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	var h []string
+	if headers != nil {
+		h = *headers
+	}
+	p := q.page(code, h)
+	var b strings.Builder
+	b.WriteString("%!PS-Adobe-3.0 EPSF-3.0\n")
+	// the integer box is rounded out, the high resolution one is the exact size
+	fmt.Fprintf(&b, "%%%%BoundingBox: 0 0 %d %d\n", int(math.Ceil(p.width)), int(math.Ceil(p.height)))
+	fmt.Fprintf(&b, "%%%%HiResBoundingBox: 0 0 %.3f %.3f\n", p.width, p.height)
+	b.WriteString("%%Creator: qrstr\n%%Pages: 1\n%%EndComments\n")
+	fg, bg := q.palette()
+	if _, _, _, a := bg.RGBA(); a != 0 {
+		fmt.Fprintf(&b, "%s setrgbcolor 0 0 %.3f %.3f rectfill\n", rgb(bg), p.width, p.height)
+	}
+	fmt.Fprintf(&b, "%s setrgbcolor\n", rgb(fg))
+	if len(h) > 0 {
+		fmt.Fprintf(&b, "/Courier findfont %.2f scalefont setfont\n", p.size)
+	}
+	p.text(func(s string, x, y float64) {
+		fmt.Fprintf(&b, "%.3f %.3f moveto (%s) show\n", x, y, psString(s, '~'))
+	})
+	p.rects(func(x, y, w, h float64) {
+		fmt.Fprintf(&b, "%.3f %.3f %.3f %.3f rectfill\n", x, y, w, h)
+	})
+	b.WriteString("%%EOF\n")
+	return b.String(), nil
+}
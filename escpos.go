@@ -0,0 +1,45 @@
+package qrstr
+
+import (
+	"bytes"
+	"image"
+)
+
+// escpos draws the code for ESC/POS receipt printers as a raster bit image, centred,
+// with any headers as code page 437 text lines above it.
+func (q *Encoder) escpos(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	img := scaleImage(q.image(code), scale).(*image.Paletted)
+	var b bytes.Buffer
+	// initialize, then centre everything
+	b.WriteString("\x1b@\x1ba\x01")
+	if headers != nil {
+		for _, v := range *headers {
+			b.Write(toCP437(v))
+			b.WriteByte('\n')
+		}
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	wb := (w + 7) / 8
+	// GS v 0 at normal density, the width in bytes and the height in dots
+	b.Write([]byte{0x1d, 'v', '0', 0, byte(wb), byte(wb >> 8), byte(h), byte(h >> 8)})
+	row := make([]byte, wb)
+	for y := 0; y < h; y++ {
+		clear(row)
+		for x := 0; x < w; x++ {
+			if img.ColorIndexAt(x, y) == 1 {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		b.Write(row)
+	}
+	// back to the left margin and feed the code past the tear bar
+	b.WriteString("\x1ba\x00\x1bd\x03")
+	return b.String(), nil
+}
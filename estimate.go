@@ -0,0 +1,92 @@
+package qrstr
+
+import (
+	"image/color"
+	"strconv"
+)
+
+// SizeEstimate holds the approximate sizes in bytes of a code in different output formats.
+type SizeEstimate struct {
+	// Version is the qr version from 1 to 40 and Size the width of the code in modules.
+	Version int
+	Size    int
+	// Text is TextDarkMode or TextLightMode with the glyphs of the encoder, TerminalMode adds about 10 bytes a line.
+	Text int
+	// HTML is HTMLMode with the headers given.
+	HTML int
+	// SVG is SVGMode.
+	SVG int
+	// PNG is EncodePNG at the scale of WithScale, the roughest of the estimates.
+	PNG int
+}
+
+// EstimateOutputSize returns the approximate size of data in each output format with the settings
+// of the encoder, so a format can be picked to fit a budget before rendering it. The code is
+// encoded to find its size but not drawn. Text, HTML and SVG are within about a tenth,
+// PNG within about a third at scales up to 8 and often over for larger ones.
+func (q *Encoder) EstimateOutputSize(data string, headers ...string) (SizeEstimate, error) {
+	code, err := q.code(data)
+	if err != nil {
+		return SizeEstimate{}, err
+	}
+	n := code.Bounds().Dx()
+	e := SizeEstimate{Version: versionOf(n), Size: n}
+	headers = q.prepare(code, headers)
+	head := 0
+	for _, h := range headers {
+		head += len(h)
+	}
+
+	g := q.glyphs.set()
+	// tenths of a byte per column, block characters take 3 bytes and blanks 1
+	cell := map[Glyphs]int{FullBlocks: 20, BlockPairs: 20, Sextants: 38, Braille: 30, Quadrants: 28}[q.glyphs]
+	if cell == 0 {
+		cell = 25
+	}
+	cols := (n+g.w-1)/g.w + 2
+	if q.glyphs == BlockPairs {
+		cols *= 2
+	}
+	line := cols*cell/10 + 1
+	e.Text = (g.rows(code)+2)*line + head
+	if head > 0 {
+		// the box around the headers and the frame down each side
+		e.Text += (4+len(headers))*line + 2*3*(g.rows(code)+2)
+	}
+
+	digits := func(v int) int { return len(strconv.Itoa(v)) }
+	path := 0
+	for y := 0; y < n; y++ {
+		path += 3 + digits(y+1)
+		c := code.At(0, y)
+		for x := 1; x <= n; x++ {
+			if x < n && code.At(x, y) == c {
+				continue
+			}
+			// H to the end of a dark run, M to the start of the next one
+			if c == color.Black {
+				path += 1 + digits(x)
+			} else {
+				path += 2 + digits(x) + digits(y+1)
+			}
+			if x < n {
+				c = code.At(x, y)
+			}
+		}
+	}
+	// the svg and rect elements and the path around its data
+	e.SVG = 234 + 4*digits(n) + path
+	e.HTML = e.SVG + 148 + 2*head + 8*len(headers)
+
+	scale := q.scale
+	if scale <= 0 {
+		scale = 8
+	}
+	w := n + 2*q.quiet
+	e.PNG = 70 + w*w/8*(10+scale)/10
+	if scale > 8 {
+		// deflate stops finding the repeated rows, take a tenth of the raw pixel data
+		e.PNG = 70 + w*scale*(1+(w*scale+7)/8)/10
+	}
+	return e, nil
+}
@@ -0,0 +1,149 @@
+package qrstr
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnknownExtension is returned by Result.WriteFile for paths it can't tell the format of.
+var ErrUnknownExtension = fmt.Errorf("unknown file extension, use .txt, .html, .svg, .png or .pdf")
+
+// WithBOM starts files of text, HTML and SVG modes, as EncodeTo and WriteBatch write them,
+// with a UTF-8 byte order mark, so Windows viewers that guess the encoding show the block
+// characters. CP437Mode and ANSIMode are not UTF-8 and never get one.
+func WithBOM() Option {
+	return func(q *Encoder) {
+		q.bom = true
+	}
+}
+
+// WithMetaCharset starts files of HTML modes, as EncodeTo and WriteBatch write them, with a
+// <meta charset="utf-8"> declaration, for browsers that would otherwise guess the encoding.
+// It is left out with WithXML, whose output must have a single root element, and WithDocument,
+// whose documents declare it already.
+func WithMetaCharset() Option {
+	return func(q *Encoder) {
+		q.metaCharset = true
+	}
+}
+
+// utf8Text reports whether output of the encoder type is UTF-8 text.
+func (t EncoderType) utf8Text() bool {
+	switch t {
+	case TextDarkMode, TextLightMode, TerminalMode, BrailleMode, SVGMode, TikZMode:
+		return true
+	}
+	return t.ext() == ".html"
+}
+
+// file returns output of mode t as it is written to a file, with the declarations of WithBOM
+// and WithMetaCharset.
+func (q *Encoder) file(t EncoderType, output string) string {
+	var b strings.Builder
+	if q.bom && t.utf8Text() {
+		b.WriteString("\ufeff")
+	}
+	if q.metaCharset && t.ext() == ".html" && !q.xml && q.document == nil {
+		b.WriteString("<meta charset=\"utf-8\">\n")
+	}
+	if b.Len() == 0 {
+		return output
+	}
+	return b.String() + output
+}
+
+// EncodeTo encodes data like Encode and writes it to w as a file, with the declarations of
+// WithBOM and WithMetaCharset if they are set.
+func (q *Encoder) EncodeTo(w io.Writer, data string, headers ...string) error {
+	s, err := q.Encode(data, headers...)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, q.file(q.mode, s))
+	return err
+}
+
+// WriteFile writes the code to the file at path in the format of its extension: .txt for text,
+// .html, .svg, .png or .pdf, with the settings of the encoder that made it. Output already in
+// that format is written as it is. The file is replaced atomically, so readers never see
+// part of it.
+func (r *Result) WriteFile(path string) error {
+	q := r.enc
+	if q == nil {
+		return ErrCodeNil
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".png" || ext == ".pdf" {
+		if err := q.checkOverlay(r.code); err != nil {
+			return err
+		}
+	}
+	var b bytes.Buffer
+	switch {
+	case ext == r.Mode.ext() && r.Mode != TerminalMode && r.Mode != CP437Mode:
+		// terminal colours and code page 437 don't belong in a .txt file
+		b.WriteString(q.file(r.Mode, r.Output))
+	case ext == ".txt":
+		eol := q.lineEnd("\n")
+		var s strings.Builder
+		err := q.textLines(q.textRC(), &r.code, &r.headers, func(line string) error {
+			s.WriteString(line + eol)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		b.WriteString(q.file(TextDarkMode, s.String()))
+	case ext == ".html":
+		s := q.htmlCode(&r.code, &r.headers, htmlFlags{})
+		if q.document != nil {
+			s = q.wrap(s, r.code, r.headers)
+		}
+		b.WriteString(q.file(HTMLMode, s))
+	case ext == ".svg":
+		s := q.svgImage(&r.code, q.altText(&r.code, nil), ` id="`+q.codeID(r.code, nil)+`"`)
+		b.WriteString(q.file(SVGMode, s))
+	case ext == ".png":
+		scale := q.scale
+		if scale <= 0 {
+			scale = 8
+		}
+		if err := png.Encode(&b, scaleImage(q.image(&r.code), scale)); err != nil {
+			return err
+		}
+	case ext == ".pdf":
+		if err := q.pdf(&b, &r.code, r.headers); err != nil {
+			return err
+		}
+	default:
+		return ErrUnknownExtension
+	}
+	return writeAtomic(path, b.Bytes())
+}
+
+// writeAtomic writes b to a temporary file next to path and renames it over path.
+func writeAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.Write(b); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,192 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// Glyphs selects the characters text modes draw modules with.
+// Each kind fits a different number of modules into one character cell,
+// so the code comes out square on terminals with different cell shapes.
+type Glyphs int
+
+const (
+	// HalfBlocks draws two modules per cell, one above the other, with ▀ and ▄.
+	// Modules are square on cells twice as tall as wide, which most terminals have. Default.
+	HalfBlocks Glyphs = 0
+	// FullBlocks draws one module per cell with █, for square cells.
+	FullBlocks Glyphs = 1
+	// BlockPairs draws one module as two cells side by side with ██, for cells twice as
+	// tall as wide where the font draws half blocks poorly.
+	BlockPairs Glyphs = 2
+	// Sextants draws six modules per cell, two across and three down, with the sextant
+	// characters from U+1FB00. Modules are square on cells 1.5 times as tall as wide.
+	// The terminal font must support Unicode 13.
+	Sextants Glyphs = 3
+	// Braille draws eight modules per cell, two across and four down, with the braille
+	// patterns from U+2800. Modules are square on cells twice as tall as wide, like HalfBlocks,
+	// but the dots leave gaps that some readers struggle with.
+	Braille Glyphs = 4
+	// Quadrants draws four modules per cell, two across and two down, with the quadrant
+	// blocks like ▚ and ▟. Codes take half the columns of HalfBlocks, but modules are twice
+	// as tall as wide on most terminals, which readers cope with.
+	Quadrants Glyphs = 5
+)
+
+// String returns the name of the glyphs, like half-block.
+func (g Glyphs) String() string {
+	switch g {
+	case HalfBlocks:
+		return "half-block"
+	case FullBlocks:
+		return "full-block"
+	case BlockPairs:
+		return "block-pair"
+	case Sextants:
+		return "sextant"
+	case Braille:
+		return "braille"
+	case Quadrants:
+		return "quadrant"
+	}
+	return fmt.Sprintf("Glyphs(%d)", int(g))
+}
+
+// glyphSet draws w by h modules in one cell.
+type glyphSet struct {
+	w, h int
+	// glyph returns the cell for the dark modules in bits, numbered left to right then top to bottom.
+	// The characters of rc decide if dark modules get ink, or light ones as in dark mode.
+	glyph func(rc *runeCol, bits int) string
+}
+
+// inverted reports whether rc draws light modules with ink, as dark mode does.
+func (c *runeCol) inverted() bool {
+	return (*c)[0] != blank
+}
+
+// quadrants holds the quadrant blocks for the bits of the top left, top right, bottom left
+// and bottom right modules.
+const quadrants = " ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█"
+
+func (g Glyphs) set() glyphSet {
+	switch g {
+	case FullBlocks:
+		return glyphSet{1, 1, func(rc *runeCol, bits int) string {
+			return string((*rc)[bits*3])
+		}}
+	case BlockPairs:
+		return glyphSet{1, 1, func(rc *runeCol, bits int) string {
+			return pad(2, (*rc)[bits*3])
+		}}
+	case Sextants:
+		return glyphSet{2, 3, func(rc *runeCol, bits int) string {
+			if rc.inverted() {
+				bits ^= 63
+			}
+			switch bits {
+			case 0:
+				return string(blank)
+			case 63:
+				return string(whole)
+			case 21:
+				return "▌"
+			case 42:
+				return "▐"
+			}
+			// the block skips the patterns that already exist as half blocks
+			r := rune(0x1fb00 + bits - 1)
+			if bits > 21 {
+				r--
+			}
+			if bits > 42 {
+				r--
+			}
+			return string(r)
+		}}
+	case Quadrants:
+		return glyphSet{2, 2, func(rc *runeCol, bits int) string {
+			if rc.inverted() {
+				bits ^= 15
+			}
+			return string([]rune(quadrants)[bits])
+		}}
+	case Braille:
+		return glyphSet{2, 4, func(rc *runeCol, bits int) string {
+			if rc.inverted() {
+				bits ^= 255
+			}
+			// the dots are numbered down the left column then the right, with the bottom row last
+			r := rune(0x2800)
+			for i, dot := range [8]rune{0x01, 0x08, 0x02, 0x10, 0x04, 0x20, 0x40, 0x80} {
+				if bits&(1<<i) != 0 {
+					r |= dot
+				}
+			}
+			return string(r)
+		}}
+	}
+	return glyphSet{1, 2, func(rc *runeCol, bits int) string {
+		return string((*rc)[bits])
+	}}
+}
+
+// rows returns the number of cell rows needed to draw code.
+func (g glyphSet) rows(code image.Image) int {
+	return (code.Bounds().Dy() + g.h - 1) / g.h
+}
+
+// row returns cell row y of code, without any quiet zone.
+func (g glyphSet) row(rc *runeCol, code image.Image, y int) string {
+	dx := code.Bounds().Dx()
+	dy := code.Bounds().Dy()
+	var b strings.Builder
+	y *= g.h
+	for x := 0; x < dx; x += g.w {
+		bits := 0
+		for j := 0; j < g.h; j++ {
+			for i := 0; i < g.w; i++ {
+				// modules past the edge are light, like the quiet zone
+				if x+i < dx && y+j < dy && code.At(x+i, y+j) == color.Black {
+					bits |= 1 << (j*g.w + i)
+				}
+			}
+		}
+		b.WriteString(g.glyph(rc, bits))
+	}
+	return b.String()
+}
+
+// WithGlyphs sets the characters text and terminal modes draw modules with.
+func WithGlyphs(g Glyphs) Option {
+	return func(q *Encoder) {
+		q.glyphs = g
+	}
+}
+
+// WithCellAspect picks the glyphs that draw the most square modules on a terminal whose
+// character cells are ratio times as tall as they are wide. Most terminals are about 2.
+// If ratio is 0 the ratio is read from the terminal on standard output, falling back to 2.
+func WithCellAspect(ratio float64) Option {
+	return func(q *Encoder) {
+		if ratio <= 0 {
+			ratio = termCellAspect()
+		}
+		if ratio <= 0 {
+			ratio = 2
+		}
+		best := math.Inf(1)
+		for _, g := range []Glyphs{HalfBlocks, FullBlocks, Sextants} {
+			s := g.set()
+			// module height over width, squared off by the log so 2:1 and 1:2 are equally bad
+			d := math.Abs(math.Log(ratio * float64(s.w) / float64(s.h)))
+			if d < best {
+				best = d
+				q.glyphs = g
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package qrstr
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// GoLiteral selects what EncodeGo declares.
+type GoLiteral int
+
+const (
+	// GoString declares a string constant holding the output of Encode. Default.
+	GoString GoLiteral = 0
+	// GoMatrix declares a [][]bool variable holding the rows of EncodeRows, true for dark modules.
+	GoMatrix GoLiteral = 1
+)
+
+// EncodeGo writes a formatted Go source file in package pkg declaring name as data rendered
+// as kind, so static codes can be generated once, with go generate, and embedded in a program.
+func (q *Encoder) EncodeGo(w io.Writer, pkg, name string, kind GoLiteral, data string, headers ...string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by qrstr. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&b, "// %s is the qr code for %s.\n", name, strconv.Quote(q.Redacted(data)))
+	switch kind {
+	case GoMatrix:
+		fmt.Fprintf(&b, "var %s = [][]bool{\n", name)
+		err := q.EncodeRows(data, func(row []bool) error {
+			s := make([]string, len(row))
+			for i, v := range row {
+				s[i] = strconv.FormatBool(v)
+			}
+			b.WriteString("{" + strings.Join(s, ", ") + "},\n")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		b.WriteString("}\n")
+	default:
+		s, err := q.Encode(data, headers...)
+		if err != nil {
+			return err
+		}
+		// a raw string keeps the code readable in the source when it can hold the output,
+		// trailing spaces are left out as editors strip them
+		lit := strconv.Quote(s)
+		if utf8.ValidString(s) && !strings.ContainsAny(s, "`\r\033") && !strings.Contains(s, " \n") {
+			lit = "`" + s + "`"
+		}
+		fmt.Fprintf(&b, "const %s = %s\n", name, lit)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,101 @@
+package qrstr
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// gridStyle styles the grid of HTMLGridMode, b elements are dark modules and i elements light ones.
+// It is formatted with the light and dark module colours. The grid is always laid out left to
+// right, a right to left page would mirror the code.
+const gridStyle = `.qr-grid{display:grid;direction:ltr;background:%s;}.qr-grid>*{aspect-ratio:1;}.qr-grid>b{background:%s;}`
+
+func (q *Encoder) htmlGrid(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	return q.gridCode(code, headers, htmlFlags{}), nil
+}
+
+// gridCode returns the div of HTMLGridMode.
+func (q *Encoder) gridCode(code *image.Image, headers *[]string, f htmlFlags) string {
+	dx := (*code).Bounds().Dx()
+	var b strings.Builder
+	nonce := ""
+	if q.nonce != "" {
+		nonce = ` nonce="` + html.EscapeString(q.nonce) + `"`
+	}
+	// the column count is in a class named after it, so codes of different sizes on one page don't clash
+	fg, bg := q.colors()
+	style := ""
+	if !f.shared {
+		style = fmt.Sprintf("<style%s>%s</style>\n", nonce,
+			q.scope(fmt.Sprintf(gridStyle+".qr-grid-%d{grid-template-columns: repeat(%d, 1fr);}", bg, fg, dx, dx)))
+	}
+	id := q.codeID(*code, deref(headers))
+	b.WriteString(q.htmlOpen(dx+1, id, headers, style, f))
+	alt := q.altText(code, headers)
+	q.gridBody(&b, *code, ` role="img" aria-label="`+html.EscapeString(alt)+`"`)
+	b.WriteString(q.fallback(alt, headers, f.shared))
+	if l := label(code); l != "" {
+		b.WriteString(q.headerOpen(id+"-label") + html.EscapeString(l) + "</p>")
+	}
+	b.WriteString("</div>")
+	return b.String()
+}
+
+// gridBody writes the grid of modules of HTMLGridMode, with the attributes attrs.
+func (q *Encoder) gridBody(b *strings.Builder, code image.Image, attrs string) {
+	dx := code.Bounds().Dx()
+	dy := code.Bounds().Dy()
+	p := q.prefix()
+	fmt.Fprintf(b, `<div class="%s-grid %s-grid-%d"%s>`, p, p, dx, attrs)
+	for y := 0; y < dy; y++ {
+		for x := 0; x < dx; x++ {
+			if code.At(x, y) == color.Black {
+				b.WriteString("<b></b>")
+			} else {
+				b.WriteString("<i></i>")
+			}
+		}
+	}
+	b.WriteString("</div>")
+}
+
+// htmlFragment writes only the grid of HTMLGridMode, without its container or styles, and the
+// label of WithShortCode after it.
+func (q *Encoder) htmlFragment(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if headers != nil && len(*headers) > 0 {
+		return "", ErrHeadersNotSupported
+	}
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	var b strings.Builder
+	id := q.codeID(*code, nil)
+	q.gridBody(&b, *code, ` id="`+id+`" role="img" aria-label="`+html.EscapeString(q.altText(code, nil))+`"`)
+	if l := label(code); l != "" {
+		b.WriteString(q.headerOpen(id+"-label") + html.EscapeString(l) + "</p>")
+	}
+	return b.String(), nil
+}
+
+// GetCSS returns the style sheet for HTMLFragmentMode output, in the colours of the encoder.
+// It styles codes of every version, so one copy serves all the codes of a page.
+func (q *Encoder) GetCSS() string {
+	return q.scope(q.gridCSS())
+}
+
+// gridCSS returns the style sheet of GetCSS with the default class names.
+func (q *Encoder) gridCSS() string {
+	fg, bg := q.colors()
+	var b strings.Builder
+	fmt.Fprintf(&b, gridStyle, bg, fg)
+	for n := 21; n <= 177; n += 4 {
+		fmt.Fprintf(&b, ".qr-grid-%d{grid-template-columns: repeat(%d, 1fr);}", n, n)
+	}
+	return b.String()
+}
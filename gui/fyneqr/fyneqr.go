@@ -0,0 +1,23 @@
+// Package fyneqr shows qrstr codes in Fyne applications.
+package fyneqr
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"git.sophuwu.com/qrstr"
+)
+
+// Image returns data encoded by q as a Fyne image that scales without blurring
+// and keeps its square shape. Its minimum size gives each module size units.
+func Image(q *qrstr.Encoder, data string, size float32) (*canvas.Image, error) {
+	img, err := q.EncodeImage(data)
+	if err != nil {
+		return nil, err
+	}
+	c := canvas.NewImageFromImage(img)
+	c.ScaleMode = canvas.ImageScalePixels
+	c.FillMode = canvas.ImageFillContain
+	d := float32(img.Bounds().Dx()) * size
+	c.SetMinSize(fyne.NewSize(d, d))
+	return c, nil
+}
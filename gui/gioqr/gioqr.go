@@ -0,0 +1,20 @@
+// Package gioqr shows qrstr codes in Gio applications.
+package gioqr
+
+import (
+	"gioui.org/op/paint"
+	"git.sophuwu.com/qrstr"
+)
+
+// ImageOp returns data encoded by q as a Gio image operation using nearest
+// neighbour filtering, so the code stays sharp when scaled up with a transform
+// or the widget.Image scale.
+func ImageOp(q *qrstr.Encoder, data string) (paint.ImageOp, error) {
+	img, err := q.EncodeImage(data)
+	if err != nil {
+		return paint.ImageOp{}, err
+	}
+	op := paint.NewImageOp(img)
+	op.Filter = paint.FilterNearest
+	return op, nil
+}
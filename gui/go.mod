@@ -0,0 +1,23 @@
+module git.sophuwu.com/qrstr/gui
+
+go 1.24.2
+
+require (
+	fyne.io/fyne/v2 v2.8.1
+	gioui.org v0.10.2
+	git.sophuwu.com/qrstr v0.0.0
+)
+
+require (
+	github.com/boombuler/barcode v1.0.2 // indirect
+	github.com/fyne-io/gl-js v0.2.1-0.20260315212741-029c47fd27e8 // indirect
+	github.com/fyne-io/oksvg v0.2.0 // indirect
+	github.com/go-gl/gl v0.0.0-20260331235117-4566fea9a276 // indirect
+	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef // indirect
+	golang.org/x/image v0.26.0 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+)
+
+replace git.sophuwu.com/qrstr => ../
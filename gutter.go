@@ -0,0 +1,86 @@
+package qrstr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Gutter is where text modes put headers.
+type Gutter int
+
+const (
+	// GutterNone puts headers in a box above the code. Default.
+	GutterNone Gutter = 0
+	// GutterLeft puts headers in a column left of the code.
+	GutterLeft Gutter = 1
+	// GutterRight puts headers in a column right of the code.
+	GutterRight Gutter = 2
+)
+
+// String returns the name of the gutter, like left.
+func (g Gutter) String() string {
+	switch g {
+	case GutterNone:
+		return "none"
+	case GutterLeft:
+		return "left"
+	case GutterRight:
+		return "right"
+	}
+	return fmt.Sprintf("Gutter(%d)", int(g))
+}
+
+// gutterMin is the narrowest header column, with less room headers go above the code.
+const gutterMin = 8
+
+// WithGutter puts the headers of text modes in a column beside the code instead of above it,
+// for receipt style layouts short on lines. The headers are wrapped to fit the code and the
+// column in width columns, or the width of the terminal on standard output if width is 0,
+// falling back to 80. If that leaves less than 8 columns the headers go above the code.
+func WithGutter(side Gutter, width int) Option {
+	return func(q *Encoder) {
+		q.gutter = side
+		q.gutterWidth = width
+	}
+}
+
+// gutterLines emits the code lines beside the headers wrapped to the gutter column.
+// It returns false without emitting anything if the column does not fit.
+func (q *Encoder) gutterLines(code []string, headers []string, emit func(line string) error) (bool, error) {
+	width := q.gutterWidth
+	if width <= 0 {
+		width = termWidth()
+	}
+	if width <= 0 {
+		width = 80
+	}
+	cw := utf8.RuneCountInString(code[0])
+	col := width - cw - 1
+	if col < gutterMin {
+		return false, nil
+	}
+	hl, err := q.headerLines(col, headers)
+	if err != nil {
+		return true, err
+	}
+	for i := 0; i < len(code) || i < len(hl); i++ {
+		c := strings.Repeat(string(blank), cw)
+		if i < len(code) {
+			c = code[i]
+		}
+		h := ""
+		if i < len(hl) {
+			h = hl[i]
+		}
+		h += pad(col-DisplayWidth(h), blank)
+		line := c + string(blank) + h
+		if q.gutter == GutterLeft {
+			line = h + string(blank) + c
+		}
+		if err = emit(line); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,129 @@
+package qrstr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HeaderPolicy decides what text modes do with headers wider than the code.
+type HeaderPolicy int
+
+const (
+	// HeaderWrap wraps long headers onto more lines with WrapText. Default.
+	HeaderWrap HeaderPolicy = 0
+	// HeaderTruncate keeps each header to one line, cutting long ones short with an ellipsis.
+	HeaderTruncate HeaderPolicy = 1
+	// HeaderError makes encoding fail with ErrHeaderTooLong if a header does not fit on one line.
+	HeaderError HeaderPolicy = 2
+)
+
+// String returns the name of the policy, like wrap.
+func (p HeaderPolicy) String() string {
+	switch p {
+	case HeaderWrap:
+		return "wrap"
+	case HeaderTruncate:
+		return "truncate"
+	case HeaderError:
+		return "error"
+	}
+	return fmt.Sprintf("HeaderPolicy(%d)", int(p))
+}
+
+var ErrHeaderTooLong = fmt.Errorf("header is too long to fit above the code")
+
+// WithHeaderPolicy sets what text modes do with headers wider than the code, and the most
+// lines the headers may take, 0 for no limit. Past the limit the last line shown ends with an
+// ellipsis, or encoding fails with ErrHeaderTooLong under HeaderError.
+// HTML modes leave layout to the browser and ignore the policy.
+func WithHeaderPolicy(p HeaderPolicy, maxLines int) Option {
+	return func(q *Encoder) {
+		q.headerPolicy = p
+		q.headerMax = maxLines
+	}
+}
+
+// headerLines lays out headers in lines of at most width columns following the header policy.
+func (q *Encoder) headerLines(width int, headers []string) ([]string, error) {
+	var lines []string
+	switch q.headerPolicy {
+	case HeaderTruncate:
+		for _, v := range headers {
+			lines = append(lines, ellipsis(v, width))
+		}
+	case HeaderError:
+		for _, v := range headers {
+			if DisplayWidth(v) > width {
+				return nil, ErrHeaderTooLong
+			}
+		}
+		lines = headers
+	default:
+		lines = WrapTextHyphen(width, q.hyphen, headers...)
+	}
+	if q.headerMax > 0 && len(lines) > q.headerMax {
+		if q.headerPolicy == HeaderError {
+			return nil, ErrHeaderTooLong
+		}
+		lines = lines[:q.headerMax]
+		last := strings.TrimRight(lines[len(lines)-1], " ")
+		if DisplayWidth(last) < width {
+			last += "…"
+		} else {
+			// cutting to one less than fits makes ellipsis drop the last character for …
+			last = ellipsis(last+" ", width)
+		}
+		lines[len(lines)-1] = last
+	}
+	return lines, nil
+}
+
+// WithHyphen sets what ends the first part of a word too long for a line when HeaderWrap breaks
+// it, "-" by default, or "" to break it without a mark. Text in Chinese, Japanese and Korean
+// breaks between characters without one.
+func WithHyphen(h string) Option {
+	return func(q *Encoder) {
+		q.hyphen = h
+	}
+}
+
+// ellipsis cuts s to width columns, ending it with … if anything was cut.
+// Emoji sequences and combining marks are kept whole or cut off whole.
+func ellipsis(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	cut, n := 0, 0
+	graphemes(s, func(start, end, w int) {
+		if n+w <= width-1 && cut == start {
+			cut, n = end, n+w
+		}
+	})
+	return strings.TrimRight(s[:cut], " ") + "…"
+}
+
+// WithFrame draws the box around headers in text modes with r instead of block characters,
+// like ' ' to leave it out or '░' to match the shading of a TUI. r should be one column wide.
+func WithFrame(r rune) Option {
+	return func(q *Encoder) {
+		q.frame = r
+	}
+}
+
+// WithPlainHeaders prints headers in text modes as plain lines above the code, without the box
+// around them or any padding, for minimal output and for piping into other formatters.
+// They are laid out to the width of the code under the header policy.
+func WithPlainHeaders() Option {
+	return func(q *Encoder) {
+		q.plainHeaders = true
+	}
+}
+
+// WithPadding draws the quiet zone around the code in text modes with r instead of the
+// character for light modules. Readers need the quiet zone to look light, Lint warns about
+// characters that may not. r should be one column wide.
+func WithPadding(r rune) Option {
+	return func(q *Encoder) {
+		q.padding = r
+	}
+}
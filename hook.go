@@ -0,0 +1,114 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// ErrTooManySkipped is returned when the module hook of WithModuleHook skips more modules than
+// the error correction level can recover.
+var ErrTooManySkipped = fmt.Errorf("module hook skips too many modules for the error correction level")
+
+// Shape is the shape a module is drawn as.
+type Shape int
+
+const (
+	// ShapeSquare fills the whole module. Default.
+	ShapeSquare Shape = 0
+	// ShapeCircle draws a circle touching the sides of the module.
+	ShapeCircle Shape = 1
+	// ShapeDiamond draws a square turned on its corner, touching the middle of each side.
+	ShapeDiamond Shape = 2
+)
+
+// String returns the name of the shape, like circle.
+func (s Shape) String() string {
+	switch s {
+	case ShapeSquare:
+		return "square"
+	case ShapeCircle:
+		return "circle"
+	case ShapeDiamond:
+		return "diamond"
+	}
+	return fmt.Sprintf("Shape(%d)", int(s))
+}
+
+// Style is how a module hook wants a module drawn.
+type Style struct {
+	// Fill is the colour of the module, nil for the colour of WithColors. Light modules are
+	// only drawn if it is set.
+	Fill color.Color
+	// Shape is the shape of the module.
+	Shape Shape
+	// Skip leaves a dark module out. Modules of the finder patterns are drawn anyway.
+	Skip bool
+}
+
+// ModuleHook returns the style of the module in column x of row y of a code, from the top left
+// without the quiet zone. It may be called more than once for each module.
+type ModuleHook func(x, y int, dark bool) Style
+
+// WithModuleHook draws each module of SVGMode, HTMLMode and HTMLSVGMode output as h styles it,
+// instead of one path for the whole code. Encoding returns ErrTooManySkipped if h skips more
+// dark modules than half of what the error correction level recovers.
+func WithModuleHook(h ModuleHook) Option {
+	return func(q *Encoder) {
+		q.hook = h
+	}
+}
+
+// hooked reports whether the encoder draws its modules with a module hook.
+func (q *Encoder) hooked() bool {
+	return q.hook != nil && (q.mode == SVGMode || q.mode == HTMLMode || q.mode == HTMLSVGMode)
+}
+
+// hookFits reports whether the module hook leaves enough of code for its error correction level.
+func (q *Encoder) hookFits(code image.Image) bool {
+	size := code.Bounds().Dx()
+	n := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dark := code.At(x, y) == color.Black
+			if dark && regionAt(x, y, size) != RegionFinder && q.hook(x, y, dark).Skip {
+				n++
+			}
+		}
+	}
+	frac := [4]float64{0.07, 0.15, 0.25, 0.30}[q.errCorr]
+	return float64(n) <= float64(size*size)*frac/2
+}
+
+// svgModules draws each module of code as the module hook styles it, dark ones in fg.
+func (q *Encoder) svgModules(code image.Image, fg string) string {
+	size := code.Bounds().Dx()
+	var b strings.Builder
+	b.WriteString("<g" + q.svgPaint("fill", fg) + ">")
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dark := code.At(x, y) == color.Black
+			s := q.hook(x, y, dark)
+			if dark && s.Skip && regionAt(x, y, size) != RegionFinder || !dark && s.Fill == nil {
+				continue
+			}
+			fill := ""
+			if s.Fill != nil {
+				fill = ` fill="` + cssColor(s.Fill) + `"`
+			}
+			// rows are drawn between y+0.5 and y+1.5 to match the path of svgImage
+			switch s.Shape {
+			case ShapeCircle:
+				fmt.Fprintf(&b, `<circle cx="%g" cy="%d" r="0.5"%s></circle>`, float64(x)+0.5, y+1, fill)
+			case ShapeDiamond:
+				fmt.Fprintf(&b, `<polygon points="%g,%g %g,%d %g,%g %d,%d"%s></polygon>`,
+					float64(x)+0.5, float64(y)+0.5, float64(x)+1, y+1, float64(x)+0.5, float64(y)+1.5, x, y+1, fill)
+			default:
+				fmt.Fprintf(&b, `<rect x="%d" y="%g" width="1" height="1"%s></rect>`, x, float64(y)+0.5, fill)
+			}
+		}
+	}
+	b.WriteString("</g>")
+	return b.String()
+}
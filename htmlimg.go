@@ -0,0 +1,34 @@
+package qrstr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/png"
+)
+
+// htmlImage draws the code as an <img> tag with a PNG data URI, described by altText.
+func (q *Encoder) htmlImage(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	pic := scaleImage(q.image(code), scale)
+	var img bytes.Buffer
+	if err := png.Encode(&img, pic); err != nil {
+		return "", err
+	}
+	alt := q.altText(code, headers)
+	end := ">"
+	if q.xml {
+		end = " />"
+	}
+	return fmt.Sprintf(`<img id="%s" src="data:image/png;base64,%s" width="%d" height="%d" alt="%s"%s`,
+		q.codeID(*code, deref(headers)), base64.StdEncoding.EncodeToString(img.Bytes()),
+		pic.Bounds().Dx(), pic.Bounds().Dy(), html.EscapeString(alt), end), nil
+}
@@ -0,0 +1,191 @@
+package qrstr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Handler serves qr codes over HTTP for GET and HEAD requests.
+//
+// The payload is read from the data query parameter and each header query parameter is
+// displayed above the code. The format parameter picks the output: text, terminal, html, svg or png.
+// Without it the format is negotiated from the Accept header, falling back to text.
+// The ecl parameter sets the error correction level to L, M, Q or H, and scale sets the
+// pixels per module of png output.
+//
+// The zero value is ready to use with the defaults described on each field.
+type Handler struct {
+	// ErrorCorrection is used when the request has no ecl parameter.
+	ErrorCorrection ErrorCorrectionLevel
+	// MaxPayload is the longest payload accepted in bytes, 1024 if zero.
+	MaxPayload int
+	// MaxAge is how long clients may cache a code, one day if zero.
+	MaxAge time.Duration
+	// CacheTTL is how long rendered codes are kept in memory for repeated requests, none are
+	// kept if zero. Identical requests arriving together always share one encode.
+	CacheTTL time.Duration
+	// CacheSize is the most codes kept in memory, 1024 if zero.
+	CacheSize int
+
+	cache handlerCache
+}
+
+// handlerFormats maps the format parameter to an encoder type.
+var handlerFormats = map[string]EncoderType{
+	"text":     TextDarkMode,
+	"terminal": TerminalMode,
+	"html":     HTMLMode,
+	"svg":      SVGMode,
+	"png":      pngMode,
+}
+
+// negotiate picks a format from an Accept header, following its order and ignoring quality values.
+func negotiate(accept string) string {
+	for _, v := range strings.Split(accept, ",") {
+		t, _, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		switch t {
+		case "image/svg+xml":
+			return "svg"
+		case "image/png":
+			return "png"
+		case "text/html":
+			return "html"
+		case "text/plain", "*/*", "text/*":
+			return "text"
+		}
+	}
+	return "text"
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	data := query.Get("data")
+	headers := query["header"]
+	maxPayload := h.MaxPayload
+	if maxPayload <= 0 {
+		maxPayload = 1024
+	}
+	if data == "" {
+		http.Error(w, "missing data parameter", http.StatusBadRequest)
+		return
+	}
+	if len(data)+len(strings.Join(headers, "")) > maxPayload {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	format := query.Get("format")
+	if format == "" {
+		format = negotiate(r.Header.Get("Accept"))
+		w.Header().Add("Vary", "Accept")
+	}
+	mode, ok := handlerFormats[format]
+	if !ok {
+		http.Error(w, "invalid format: "+format, http.StatusBadRequest)
+		return
+	}
+	ecl := h.ErrorCorrection
+	if v := query.Get("ecl"); v != "" {
+		var err error
+		if ecl, err = ParseErrorCorrectionLevel(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	scale := 8
+	if v := query.Get("scale"); v != "" && format == "png" {
+		var err error
+		if scale, err = strconv.Atoi(v); err != nil || scale < 1 || scale > 32 {
+			http.Error(w, "scale must be between 1 and 32", http.StatusBadRequest)
+			return
+		}
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%d\x00%q\x00%q", format, ecl, scale, data, headers)))
+	etag := `"` + hex.EncodeToString(sum[:12]) + `"`
+	maxAge := h.MaxAge
+	if maxAge <= 0 {
+		maxAge = 24 * time.Hour
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	size := h.CacheSize
+	if size <= 0 {
+		size = 1024
+	}
+	body, err := h.cache.do(etag, h.CacheTTL, size, func() ([]byte, error) {
+		return render(mode, ecl, scale, data, headers)
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", mode.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// render encodes data for the handler, pngMode makes a png image with scale pixels per module.
+func render(mode EncoderType, ecl ErrorCorrectionLevel, scale int, data string, headers []string) ([]byte, error) {
+	if mode == HTMLMode {
+		// headers come from the query string, escape them here so no encoder setting can
+		// put them in the page as markup, the alt text is escaped where it is used
+		escaped := make([]string, len(headers))
+		for i, v := range headers {
+			escaped[i] = html.EscapeString(v)
+		}
+		opts := []Option{WithRawHTMLHeaders()}
+		if len(headers) > 0 {
+			opts = append(opts, WithAltText(strings.Join(headers, " ")))
+		}
+		q, err := NewEncoder(mode, ecl, opts...)
+		if err != nil {
+			return nil, err
+		}
+		s, err := q.Encode(data, escaped...)
+		return []byte(s), err
+	}
+	if mode != pngMode {
+		// the terminal format is for the client's terminal, not the one the server runs in
+		q, err := NewEncoder(mode, ecl, WithForceColor())
+		if err != nil {
+			return nil, err
+		}
+		s, err := q.Encode(data, headers...)
+		return []byte(s), err
+	}
+	if len(headers) > 0 {
+		return nil, ErrHeadersNotSupported
+	}
+	q, err := NewEncoder(TextLightMode, ecl, WithScale(scale))
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	err = q.EncodePNG(&b, data)
+	return b.Bytes(), err
+}
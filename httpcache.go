@@ -0,0 +1,93 @@
+package qrstr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// flight is an encode in progress that identical requests wait on.
+type flight struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// cached is a rendered code kept by the handler until expires.
+type cached struct {
+	body    []byte
+	expires time.Time
+}
+
+// handlerCache shares encodes between identical requests, see Handler.CacheTTL.
+type handlerCache struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+	entries map[string]cached
+}
+
+// errFlightPanicked is what requests waiting on an encode get when it panicked.
+var errFlightPanicked = fmt.Errorf("encode panicked")
+
+// do returns the body for key from the cache, from an encode of it already running,
+// or from fn, keeping the result for ttl if it succeeds and ttl is positive.
+func (c *handlerCache) do(key string, ttl time.Duration, size int, fn func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.body, nil
+	}
+	if f, ok := c.flights[key]; ok {
+		c.mu.Unlock()
+		<-f.done
+		return f.body, f.err
+	}
+	if c.flights == nil {
+		c.flights = make(map[string]*flight)
+		c.entries = make(map[string]cached)
+	}
+	f := &flight{done: make(chan struct{})}
+	c.flights[key] = f
+	c.mu.Unlock()
+
+	// deferred so a panic in fn, which net/http recovers, doesn't leave waiters blocked forever
+	ok := false
+	defer func() {
+		if !ok {
+			f.body, f.err = nil, errFlightPanicked
+			c.mu.Lock()
+			delete(c.flights, key)
+			c.mu.Unlock()
+		}
+		close(f.done)
+	}()
+	f.body, f.err = fn()
+	ok = true
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.flights, key)
+	if f.err == nil && ttl > 0 {
+		if len(c.entries) >= size {
+			c.evict(size)
+		}
+		c.entries[key] = cached{f.body, time.Now().Add(ttl)}
+	}
+	return f.body, f.err
+}
+
+// evict drops expired entries, then arbitrary ones until there is room for one more than size.
+func (c *handlerCache) evict(size int) {
+	now := time.Now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	for k := range c.entries {
+		if len(c.entries) < size {
+			break
+		}
+		delete(c.entries, k)
+	}
+}
@@ -0,0 +1,47 @@
+package qrstr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"image"
+	"strconv"
+)
+
+// WithIDPrefix sets the start of the ids of SVG and HTML mode codes, qr by default.
+// Ids end in a hash of the modules, headers and mode, so they are the same on every run and
+// differ between codes on one page. Identical codes share an id, give them different prefixes
+// to tell them apart.
+func WithIDPrefix(p string) Option {
+	return func(q *Encoder) {
+		q.idPrefix = p
+	}
+}
+
+// codeID returns the id of the element around code in SVG and HTML modes.
+func (q *Encoder) codeID(code image.Image, headers []string) string {
+	h := sha256.New()
+	h.Write([]byte(q.mode.String()))
+	writeMatrix(h, code)
+	for _, v := range headers {
+		h.Write([]byte(v + "\x00"))
+	}
+	p := q.idPrefix
+	if p == "" {
+		p = "qr"
+	}
+	return html.EscapeString(p) + "-" + hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// deref returns the headers renderers are passed, nil if there are none.
+func deref(headers *[]string) []string {
+	if headers == nil {
+		return nil
+	}
+	return *headers
+}
+
+// headerID returns the id of header i of the code with the given id.
+func headerID(id string, i int) string {
+	return id + "-h" + strconv.Itoa(i+1)
+}
@@ -0,0 +1,121 @@
+package qrstr
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// quietZone is the number of blank modules around raster images, as the qr spec asks for.
+const quietZone = 4
+
+// EncodeImage returns data as a black and white image with one pixel per module
+// and a quiet zone of four modules, or the size of WithQuietZone. Scale it with nearest neighbour filtering to keep it sharp.
+// The image is paletted, colour index 1 is dark modules, in the colours of WithColors.
+func (q *Encoder) EncodeImage(data string) (image.Image, error) {
+	code, err := q.code(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = q.checkOverlay(code); err != nil {
+		return nil, err
+	}
+	return q.image(&code), nil
+}
+
+// EncodePNG writes data to w as a PNG image with 8 pixels per module, or the scale of WithScale,
+// and the quiet zone of EncodeImage.
+func (q *Encoder) EncodePNG(w io.Writer, data string) error {
+	img, err := q.EncodeImage(data)
+	if err != nil {
+		return err
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 8
+	}
+	return png.Encode(w, scaleImage(img, scale))
+}
+
+// WithScale sets the pixels per module of EncodePNG.
+func WithScale(n int) Option {
+	return func(q *Encoder) {
+		q.scale = n
+	}
+}
+
+// WithQuietZone sets the blank border of images in modules. The qr spec asks for 4,
+// readers often cope with less when the code sits on a plain background.
+func WithQuietZone(n int) Option {
+	return func(q *Encoder) {
+		q.quiet = max(n, 0)
+	}
+}
+
+// image draws code like EncodeImage, with the colours of the encoder.
+func (q *Encoder) image(code *image.Image) image.Image {
+	img := raster(code, q.quiet).(*image.Paletted)
+	if q.overlay != "" {
+		drawOverlay(img, q.overlay, q.quiet)
+	}
+	if l := label(code); l != "" {
+		img = drawLabel(img, l)
+	}
+	fg, bg := q.palette()
+	img.Palette = color.Palette{bg, fg}
+	return img
+}
+
+// raster draws code on a white paletted image with a quiet zone of qz modules.
+func raster(code *image.Image, qz int) image.Image {
+	if code == nil {
+		return nil
+	}
+	dx := (*code).Bounds().Dx()
+	dy := (*code).Bounds().Dy()
+	img := image.NewPaletted(image.Rect(0, 0, dx+2*qz, dy+2*qz), color.Palette{color.White, color.Black})
+	for y := 0; y < dy; y++ {
+		for x := 0; x < dx; x++ {
+			if (*code).At(x, y) == color.Black {
+				img.SetColorIndex(x+qz, y+qz, 1)
+			}
+		}
+	}
+	return img
+}
+
+// scaleImage enlarges a paletted image by n pixels per pixel.
+func scaleImage(img image.Image, n int) image.Image {
+	src, ok := img.(*image.Paletted)
+	if !ok || n <= 1 {
+		return img
+	}
+	b := src.Bounds()
+	dst := image.NewPaletted(image.Rect(0, 0, b.Dx()*n, b.Dy()*n), src.Palette)
+	for y := 0; y < dst.Bounds().Dy(); y++ {
+		for x := 0; x < dst.Bounds().Dx(); x++ {
+			dst.SetColorIndex(x, y, src.ColorIndexAt(b.Min.X+x/n, b.Min.Y+y/n))
+		}
+	}
+	return dst
+}
+
+// darkRuns calls fn with the start and length of each run of dark modules in each row of img,
+// which must be paletted with dark modules at index 1.
+func darkRuns(img *image.Paletted, fn func(x, y, n int)) {
+	b := img.Bounds()
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if img.ColorIndexAt(b.Min.X+x, b.Min.Y+y) != 1 {
+				continue
+			}
+			n := 1
+			for x+n < b.Dx() && img.ColorIndexAt(b.Min.X+x+n, b.Min.Y+y) == 1 {
+				n++
+			}
+			fn(x, y, n)
+			x += n
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package qrstr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+)
+
+// iterm draws the code as a PNG sent with the iTerm2 inline image escape, with any headers as plain lines above it.
+func (q *Encoder) iterm(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	pic := scaleImage(q.image(code), scale)
+	var img bytes.Buffer
+	if err := png.Encode(&img, pic); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if headers != nil {
+		for _, v := range *headers {
+			b.WriteString(v + "\n")
+		}
+	}
+	// the width and height in pixels stop iTerm2 fitting the image to the cell grid and blurring it
+	fmt.Fprintf(&b, "\033]1337;File=inline=1;size=%d;width=%dpx;height=%dpx:%s\a\n",
+		img.Len(), pic.Bounds().Dx(), pic.Bounds().Dy(), base64.StdEncoding.EncodeToString(img.Bytes()))
+	return b.String(), nil
+}
@@ -0,0 +1,52 @@
+package qrstr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"strings"
+)
+
+// kittyChunk is the most base64 bytes the kitty graphics protocol takes in one escape.
+const kittyChunk = 4096
+
+// kitty draws the code as a PNG sent with the kitty graphics protocol, with any headers as plain lines above it.
+func (q *Encoder) kitty(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	var img bytes.Buffer
+	if err := png.Encode(&img, scaleImage(q.image(code), scale)); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if headers != nil {
+		for _, v := range *headers {
+			b.WriteString(v + "\n")
+		}
+	}
+	data := base64.StdEncoding.EncodeToString(img.Bytes())
+	// a=T transmits and shows the image, f=100 is PNG, q=2 stops the terminal replying
+	b.WriteString("\033_Ga=T,f=100,q=2,")
+	for {
+		n := min(len(data), kittyChunk)
+		if n < len(data) {
+			b.WriteString("m=1;")
+		} else {
+			b.WriteString("m=0;")
+		}
+		b.WriteString(data[:n] + "\033\\")
+		data = data[n:]
+		if data == "" {
+			break
+		}
+		b.WriteString("\033_G")
+	}
+	b.WriteString("\n")
+	return b.String(), nil
+}
@@ -0,0 +1,70 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// labelFont is the height in dots of header lines in TSPL and EPL output,
+// TSPL font 3 and EPL font 4 are both 24 dots tall.
+const labelFont = 24
+
+// labelImage returns the code as scaled for label printers, 4 dots per module by default.
+func (q *Encoder) labelImage(code *image.Image) (*image.Paletted, int) {
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	return q.image(code).(*image.Paletted), scale
+}
+
+// tspl draws the code as a TSPL label for TSC printers, a bar for each run of dark modules,
+// with any headers as text above it. The label size and gap are left to the printer setup.
+func (q *Encoder) tspl(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	img, scale := q.labelImage(code)
+	var b strings.Builder
+	b.WriteString("CLS\r\n")
+	top := 0
+	if headers != nil {
+		for _, v := range *headers {
+			// a quote inside a string is written \["]
+			s := strings.ReplaceAll(string(toCP437(v)), `"`, `\["]`)
+			fmt.Fprintf(&b, "TEXT %d,%d,\"3\",0,1,1,\"%s\"\r\n", scale*q.quiet, top, s)
+			top += labelFont * 5 / 4
+		}
+	}
+	darkRuns(img, func(x, y, n int) {
+		fmt.Fprintf(&b, "BAR %d,%d,%d,%d\r\n", x*scale, top+y*scale, n*scale, scale)
+	})
+	b.WriteString("PRINT 1\r\n")
+	return b.String(), nil
+}
+
+// epl draws the code as an EPL2 label for older Zebra and Eltron printers, a black line for
+// each run of dark modules, with any headers as text above it.
+func (q *Encoder) epl(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	img, scale := q.labelImage(code)
+	var b strings.Builder
+	// a blank line ends any command left open, then N clears the image buffer
+	b.WriteString("\r\nN\r\n")
+	top := 0
+	if headers != nil {
+		for _, v := range *headers {
+			s := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(string(toCP437(v)))
+			fmt.Fprintf(&b, "A%d,%d,0,4,1,1,N,\"%s\"\r\n", scale*q.quiet, top, s)
+			top += labelFont * 5 / 4
+		}
+	}
+	darkRuns(img, func(x, y, n int) {
+		fmt.Fprintf(&b, "LO%d,%d,%d,%d\r\n", x*scale, top+y*scale, n*scale, scale)
+	})
+	b.WriteString("P1\r\n")
+	return b.String(), nil
+}
@@ -0,0 +1,53 @@
+package qrstr
+
+import "image/color"
+
+// Warning describes a setting that may make codes hard to scan.
+type Warning struct {
+	// Code names the check, like inverted.
+	Code    string
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Code + ": " + w.Message
+}
+
+// Lint checks the settings of the encoder for anything that may stop readers scanning its codes.
+// The codes are still valid, the warnings are for deciding whether a setting was meant.
+func (q *Encoder) Lint() []Warning {
+	var w []Warning
+	if q.inverted {
+		if q.rc != nil {
+			w = append(w, Warning{"inverted", "WithInverted has no effect on text modes, use TextDarkMode or TextLightMode"})
+		} else {
+			w = append(w, Warning{"inverted", "light on dark codes can't be scanned by readers without reflectance reversal, which is optional in the qr spec"})
+		}
+	}
+	if q.rc != nil && q.glyphs == Sextants {
+		w = append(w, Warning{"sextant", "sextant characters need a font with Unicode 13 symbols, others draw boxes"})
+	}
+	if q.rc != nil && q.padding != 0 && q.padding != (*q.rc)[0] {
+		w = append(w, Warning{"padding", "the padding character differs from light modules, readers may not find the edge of the code"})
+	}
+	if q.fg != nil || q.bg != nil {
+		// palette swaps for inversion, which has its own warning
+		fg, bg := q.palette()
+		if q.inverted {
+			fg, bg = bg, fg
+		}
+		_, _, _, fa := fg.RGBA()
+		_, _, _, ba := bg.RGBA()
+		// transparent modules show whatever is behind them, which can't be checked
+		if fa != 0 && ba != 0 && luma(bg)-luma(fg) < 0.4 {
+			w = append(w, Warning{"contrast", "dark modules are not much darker than light ones, readers may not tell them apart"})
+		}
+	}
+	return w
+}
+
+// luma returns the brightness of c from 0 to 1, ignoring alpha.
+func luma(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff
+}
@@ -0,0 +1,101 @@
+package qrstr
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"image"
+	"image/color"
+	"io"
+)
+
+// Matrix is the module grid of a code, for renderers that draw it themselves.
+// It marshals to JSON as {"size":N,"version":V,"ecl":"M","modules":[[true,false,...],...]}.
+type Matrix struct {
+	// Modules holds the rows of the code from the top, true for dark modules.
+	Modules [][]bool
+	// Version is the qr version from 1 to 40, which sets the size.
+	Version int
+	// ErrorCorrection is the level the code was encoded with.
+	ErrorCorrection ErrorCorrectionLevel
+}
+
+// Size returns the number of modules on each side, without a quiet zone.
+func (m Matrix) Size() int {
+	return len(m.Modules)
+}
+
+// At reports whether the module in column x of row y is dark, false outside the code.
+func (m Matrix) At(x, y int) bool {
+	if x < 0 || y < 0 || y >= len(m.Modules) || x >= len(m.Modules[y]) {
+		return false
+	}
+	return m.Modules[y][x]
+}
+
+// MarshalJSON encodes the matrix with its size and the letter of its error correction level.
+func (m Matrix) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Size            int      `json:"size"`
+		Version         int      `json:"version"`
+		ErrorCorrection string   `json:"ecl"`
+		Modules         [][]bool `json:"modules"`
+	}{m.Size(), m.Version, m.ErrorCorrection.String(), m.Modules})
+}
+
+// EncodeMatrix returns the modules of the code for data, without a quiet zone, headers,
+// overlay or colours.
+func (q *Encoder) EncodeMatrix(data string) (Matrix, error) {
+	code, err := q.code(data)
+	if err != nil {
+		return Matrix{}, err
+	}
+	return matrix(code, q.errCorr), nil
+}
+
+// Matrix returns the modules of the code, see EncodeMatrix.
+func (r *Result) Matrix() Matrix {
+	return matrix(r.code, r.ErrorCorrection)
+}
+
+// matrix reads the modules of code, encoded at level ecl.
+func matrix(code image.Image, ecl ErrorCorrectionLevel) Matrix {
+	b := code.Bounds()
+	m := Matrix{Modules: make([][]bool, b.Dy()), Version: versionOf(b.Dx()), ErrorCorrection: ecl}
+	for y := range m.Modules {
+		row := make([]bool, b.Dx())
+		for x := range row {
+			row[x] = code.At(b.Min.X+x, b.Min.Y+y) == color.Black
+		}
+		m.Modules[y] = row
+	}
+	return m
+}
+
+// versionOf returns the qr version of a code that is size modules wide.
+func versionOf(size int) int {
+	return (size - 17) / 4
+}
+
+// writeMatrix writes the modules of code to w as rows of 1 for dark and 0 for light, each
+// ending in a newline.
+func writeMatrix(w io.Writer, code image.Image) {
+	b := code.Bounds()
+	row := make([]byte, b.Dx()+1)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := range b.Dx() {
+			row[x] = '0'
+			if code.At(b.Min.X+x, y) == color.Black {
+				row[x] = '1'
+			}
+		}
+		row[b.Dx()] = '\n'
+		w.Write(row)
+	}
+}
+
+// matrixHash returns the sha256 hash of the modules of code as writeMatrix writes them.
+func matrixHash(code image.Image) [32]byte {
+	h := sha256.New()
+	writeMatrix(h, code)
+	return [32]byte(h.Sum(nil))
+}
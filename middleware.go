@@ -0,0 +1,22 @@
+package qrstr
+
+// RenderFunc renders a payload with headers to output, like Encode.
+type RenderFunc func(data string, headers []string) (string, error)
+
+// Use adds middleware around the rendering of Encode, and of EncodeResult and the batch,
+// archive and rotation helpers built on it. Middleware can rewrite the payload or headers
+// before calling next, change the output it returns, or answer without calling next,
+// to log, watermark or cache codes. The first middleware added runs first.
+// Use is not safe to call while the encoder is in use.
+func (q *Encoder) Use(mw ...func(next RenderFunc) RenderFunc) {
+	q.middleware = append(q.middleware, mw...)
+}
+
+// chain returns base wrapped in the middleware of the encoder.
+func (q *Encoder) chain(base RenderFunc) RenderFunc {
+	f := base
+	for i := len(q.middleware) - 1; i >= 0; i-- {
+		f = q.middleware[i](f)
+	}
+	return f
+}
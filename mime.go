@@ -0,0 +1,33 @@
+package qrstr
+
+// pngMode marks PNG output where an encoder type is expected, like the formats of Handler.
+// NewEncoder does not accept it.
+const pngMode EncoderType = -1
+
+// ContentType returns the MIME type of output of the encoder type, with the charset for text.
+func (t EncoderType) ContentType() string {
+	switch t {
+	case HTMLMode, HTMLGridMode, HTMLImageMode, HTMLSVGMode, HTMLFragmentMode, HTMLCanvasMode, HTMLTableMode:
+		return "text/html; charset=utf-8"
+	case SVGMode:
+		return "image/svg+xml"
+	case ANSIMode, CP437Mode:
+		return "text/plain; charset=IBM437"
+	case EPSMode:
+		return "application/postscript"
+	case TikZMode:
+		return "application/x-tex"
+	case PBMMode:
+		return "image/x-portable-bitmap"
+	case ESCPOSMode:
+		return "application/octet-stream"
+	case pngMode:
+		return "image/png"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// ContentType returns the MIME type of the output, see EncoderType.ContentType.
+func (r *Result) ContentType() string {
+	return r.Mode.ContentType()
+}
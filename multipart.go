@@ -0,0 +1,87 @@
+package qrstr
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Part is one piece of a payload that was split across several qr codes.
+type Part struct {
+	Index int
+	Count int
+	Data  string
+}
+
+// String formats the part as P<index>/<count>:<data>, which stays in the
+// alphanumeric qr mode when the data does.
+func (p Part) String() string {
+	return fmt.Sprintf("P%d/%d:%s", p.Index, p.Count, p.Data)
+}
+
+var ErrPartInvalid = fmt.Errorf("text is not a valid part")
+
+// ParsePart reads a part written by Part.String.
+func ParsePart(s string) (Part, error) {
+	var p Part
+	head, data, ok := strings.Cut(s, ":")
+	if !ok || !strings.HasPrefix(head, "P") {
+		return p, ErrPartInvalid
+	}
+	i, n, ok := strings.Cut(head[1:], "/")
+	if !ok {
+		return p, ErrPartInvalid
+	}
+	var err error
+	if p.Index, err = strconv.Atoi(i); err != nil {
+		return p, ErrPartInvalid
+	}
+	if p.Count, err = strconv.Atoi(n); err != nil {
+		return p, ErrPartInvalid
+	}
+	if p.Index < 1 || p.Index > p.Count {
+		return p, ErrPartInvalid
+	}
+	p.Data = data
+	return p, nil
+}
+
+// SplitParts splits data into n parts of about equal size.
+// Parts never split a multi byte character.
+func SplitParts(data string, n int) []Part {
+	r := []rune(data)
+	if n < 1 {
+		n = 1
+	}
+	if n > len(r) && len(r) > 0 {
+		n = len(r)
+	}
+	size := (len(r) + n - 1) / n
+	parts := make([]Part, 0, n)
+	for i := 0; i < n; i++ {
+		end := min((i+1)*size, len(r))
+		parts = append(parts, Part{Index: i + 1, Count: n, Data: string(r[min(i*size, end):end])})
+	}
+	return parts
+}
+
+var ErrPartsIncomplete = fmt.Errorf("parts are missing or belong to different payloads")
+
+// JoinParts puts the parts back together in order, they may be given in any order.
+// Every part must be present exactly once.
+func JoinParts(parts ...Part) (string, error) {
+	if len(parts) == 0 {
+		return "", ErrPartsIncomplete
+	}
+	parts = slices.Clone(parts)
+	slices.SortFunc(parts, func(a, b Part) int { return a.Index - b.Index })
+	var data strings.Builder
+	for i, p := range parts {
+		if p.Count != len(parts) || p.Index != i+1 {
+			return "", ErrPartsIncomplete
+		}
+		data.WriteString(p.Data)
+	}
+	return data.String(), nil
+}
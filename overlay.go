@@ -0,0 +1,122 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrOverlayTooBig is returned when the overlay of WithOverlay covers more of the code than its error correction can recover.
+var ErrOverlayTooBig = fmt.Errorf("overlay is too big for the code and its error correction level")
+
+// WithOverlay clears a box in the middle of raster output and writes s in it, like a serial number
+// to tell apart otherwise identical codes. Letters, digits and dashes are drawn, anything else is left blank.
+// The box covers at most half of what the error correction level recovers and stays clear of the
+// finder, timing, format and version patterns, so short text needs ErrorCorrection25Percent or more on small codes. Encoding returns
+// ErrOverlayTooBig if it does not fit. Text, SVG and HTML modes other than HTMLImageMode,
+// HTMLCanvasMode and HTMLTableMode ignore the overlay, EncodeImage, EncodePNG and EncodePDF draw it in any mode.
+func WithOverlay(s string) Option {
+	return func(q *Encoder) {
+		q.overlay = strings.ToUpper(s)
+	}
+}
+
+// overlayBox returns the box the overlay s takes in a code size modules wide, relative to the code.
+func overlayBox(s string, size int) image.Rectangle {
+	// a module of margin around the 3x5 glyphs
+	w := 4*utf8.RuneCountInString(s) + 1
+	h := 7
+	x, y := (size-w)/2, (size-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// raster reports whether the encoder type draws codes through image, with the overlay.
+func (t EncoderType) raster() bool {
+	switch t {
+	case SixelMode, KittyMode, ITermMode, EPSMode, ZPLMode, ESCPOSMode, TSPLMode, EPLMode,
+		PBMMode, TikZMode, HTMLImageMode, HTMLCanvasMode, HTMLTableMode:
+		return true
+	}
+	return false
+}
+
+// checkOverlay returns ErrOverlayTooBig if the overlay does not fit code. Modes that draw it
+// are checked when encoding, the image functions check it for the others.
+func (q *Encoder) checkOverlay(code image.Image) error {
+	if q.overlay != "" && !overlayFits(q.overlay, code.Bounds().Dx(), q.errCorr) {
+		return ErrOverlayTooBig
+	}
+	return nil
+}
+
+// overlayFits reports whether the overlay s can be cleared from a code size modules wide
+// at error correction level ecl.
+func overlayFits(s string, size int, ecl ErrorCorrectionLevel) bool {
+	return safeFits(overlayBox(s, size), size, ecl)
+}
+
+// SafeArea returns the largest square in the middle of a code of the given version, 1 to 40,
+// that can be covered, by a logo for example, and still leave it readable at error correction
+// level ecl. It is relative to the top left module, without the quiet zone, and empty for
+// versions out of range. The square covers at most half of what the error correction level
+// recovers and stays clear of the finder, timing, format and version patterns, the same limits
+// as WithOverlay.
+func SafeArea(version int, ecl ErrorCorrectionLevel) image.Rectangle {
+	if version < 1 || version > 40 || ecl < 0 || ecl > 3 {
+		return image.Rectangle{}
+	}
+	size := version*4 + 17
+	var r image.Rectangle
+	// odd sides keep the square centred on the odd width of the code
+	for n := 1; n <= size; n += 2 {
+		c := (size - n) / 2
+		box := image.Rect(c, c, c+n, c+n)
+		if !safeFits(box, size, ecl) {
+			break
+		}
+		r = box
+	}
+	return r
+}
+
+// safeFits reports whether r can be covered in a code size modules wide at error correction level ecl.
+func safeFits(r image.Rectangle, size int, ecl ErrorCorrectionLevel) bool {
+	if r.Min.X < 0 || r.Min.Y < 0 {
+		return false
+	}
+	// alignment patterns are hard to avoid in the middle, readers cope without one
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if g := regionAt(x, y, size); g != RegionData && g != RegionAlignment {
+				return false
+			}
+		}
+	}
+	frac := [4]float64{0.07, 0.15, 0.25, 0.30}[ecl]
+	return float64(r.Dx()*r.Dy()) <= float64(size*size)*frac/2
+}
+
+// drawOverlay clears the overlay box of s in img, a code with a quiet zone of qz modules,
+// and writes s in it. img must be paletted with dark modules at index 1.
+func drawOverlay(img *image.Paletted, s string, qz int) {
+	size := img.Bounds().Dx() - 2*qz
+	r := overlayBox(s, size).Add(image.Pt(qz, qz))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, 0)
+		}
+	}
+	i := 0
+	for _, c := range s {
+		for y, row := range font3x5[unicode.ToUpper(c)] {
+			for x := 0; x < 3; x++ {
+				if row&(4>>x) != 0 {
+					img.SetColorIndex(r.Min.X+1+4*i+x, r.Min.Y+1+y, 1)
+				}
+			}
+		}
+		i++
+	}
+}
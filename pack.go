@@ -0,0 +1,101 @@
+package qrstr
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+// base45 is the alphabet of RFC 9285, it is the same as the qr alphanumeric character set.
+const base45 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// Base45Encode encodes b as base45 text, which qr codes store in alphanumeric mode.
+func Base45Encode(b []byte) string {
+	var s strings.Builder
+	for i := 0; i < len(b); i += 2 {
+		if i+1 == len(b) {
+			n := int(b[i])
+			s.WriteByte(base45[n%45])
+			s.WriteByte(base45[n/45])
+			break
+		}
+		n := int(b[i])<<8 | int(b[i+1])
+		s.WriteByte(base45[n%45])
+		s.WriteByte(base45[n/45%45])
+		s.WriteByte(base45[n/2025])
+	}
+	return s.String()
+}
+
+var ErrBase45Invalid = fmt.Errorf("text is not valid base45")
+
+// Base45Decode decodes base45 text made by Base45Encode.
+func Base45Decode(s string) ([]byte, error) {
+	if len(s)%3 == 1 {
+		return nil, ErrBase45Invalid
+	}
+	b := make([]byte, 0, len(s)/3*2+1)
+	for i := 0; i < len(s); i += 3 {
+		n, f := 0, 1
+		for j := i; j < min(i+3, len(s)); j++ {
+			v := strings.IndexByte(base45, s[j])
+			if v < 0 {
+				return nil, ErrBase45Invalid
+			}
+			n += v * f
+			f *= 45
+		}
+		if i+2 == len(s) {
+			if n > 0xff {
+				return nil, ErrBase45Invalid
+			}
+			b = append(b, byte(n))
+			break
+		}
+		if n > 0xffff {
+			return nil, ErrBase45Invalid
+		}
+		b = append(b, byte(n>>8), byte(n))
+	}
+	return b, nil
+}
+
+// Packing is the text encoding PackBytes chose for binary data.
+type Packing int
+
+const (
+	// PackBase45 is base45 text, stored in the qr alphanumeric mode.
+	PackBase45 Packing = 0
+	// PackBase64URL is unpadded base64url text, stored in the qr byte mode.
+	PackBase64URL Packing = 1
+)
+
+// PackBytes packs b into qr friendly text, using base45 or base64url, whichever
+// makes the smaller symbol at the given error correction level.
+// Base45 is chosen when both are the same size. Use UnpackBytes to get b back.
+func PackBytes(b []byte, errorCorrectionLevel ErrorCorrectionLevel) (string, Packing, error) {
+	s45 := Base45Encode(b)
+	s64 := base64.RawURLEncoding.EncodeToString(b)
+	c45, err45 := qr.Encode(s45, qr.ErrorCorrectionLevel(errorCorrectionLevel), qr.AlphaNumeric)
+	c64, err64 := qr.Encode(s64, qr.ErrorCorrectionLevel(errorCorrectionLevel), qr.Unicode)
+	switch {
+	case err45 == nil && (err64 != nil || c45.Bounds().Dx() <= c64.Bounds().Dx()):
+		return s45, PackBase45, nil
+	case err64 == nil:
+		return s64, PackBase64URL, nil
+	}
+	return "", 0, err45
+}
+
+// UnpackBytes decodes text made by PackBytes.
+func UnpackBytes(s string, p Packing) ([]byte, error) {
+	switch p {
+	case PackBase45:
+		return Base45Decode(s)
+	case PackBase64URL:
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return nil, fmt.Errorf("invalid packing: %d", p)
+}
@@ -0,0 +1,74 @@
+package qrstr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+// PayloadClass is the kind of characters in a payload, which decides the qr mode that stores it best.
+type PayloadClass int
+
+const (
+	// PayloadAuto analyzes each payload to find its class. Default.
+	PayloadAuto PayloadClass = 0
+	// PayloadNumeric is only the digits 0 to 9, stored in numeric mode.
+	PayloadNumeric PayloadClass = 1
+	// PayloadAlphanumeric is digits, upper case letters, space and $%*+-./:, stored in alphanumeric mode.
+	PayloadAlphanumeric PayloadClass = 2
+	// PayloadBytes is any text, stored as UTF-8 in byte mode.
+	PayloadBytes PayloadClass = 3
+)
+
+// String returns the name of the class, like alphanumeric.
+func (c PayloadClass) String() string {
+	switch c {
+	case PayloadAuto:
+		return "auto"
+	case PayloadNumeric:
+		return "numeric"
+	case PayloadAlphanumeric:
+		return "alphanumeric"
+	case PayloadBytes:
+		return "bytes"
+	}
+	return fmt.Sprintf("PayloadClass(%d)", int(c))
+}
+
+// encoding returns the qr encoding for the class.
+func (c PayloadClass) encoding() qr.Encoding {
+	switch c {
+	case PayloadNumeric:
+		return qr.Numeric
+	case PayloadAlphanumeric:
+		return qr.AlphaNumeric
+	case PayloadBytes:
+		return qr.Unicode
+	}
+	return qr.Auto
+}
+
+// AnalyzePayload returns the most compact class that can hold data, in a single scan.
+func AnalyzePayload(data string) PayloadClass {
+	c := PayloadNumeric
+	for _, r := range data {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		if r < 0x80 && strings.IndexByte(base45, byte(r)) >= 0 {
+			c = PayloadAlphanumeric
+			continue
+		}
+		return PayloadBytes
+	}
+	return c
+}
+
+// WithPayloadClass skips analyzing payloads and encodes them all in the mode of class c.
+// Encoding fails for payloads that do not fit the class.
+func WithPayloadClass(c PayloadClass) Option {
+	return func(q *Encoder) {
+		q.class = c
+	}
+}
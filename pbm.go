@@ -0,0 +1,41 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// pbm draws the code as a plain PBM bitmap, one pixel per module, or the scale of WithScale,
+// with the quiet zone of images. 1 is a black pixel, dark modules unless WithInverted is set.
+func (q *Encoder) pbm(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	if headers != nil && len(*headers) > 0 {
+		return "", ErrHeadersNotSupported
+	}
+	img := scaleImage(q.image(code), q.scale).(*image.Paletted)
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	var ink uint8 = 1
+	if q.inverted {
+		ink = 0
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "P1\n%d %d\n", w, h)
+	for y := 0; y < h; y++ {
+		// lines of plain PBM should not be longer than 70 characters
+		for x := 0; x < w; x++ {
+			if x > 0 && x%70 == 0 {
+				b.WriteByte('\n')
+			}
+			if img.ColorIndexAt(x, y) == ink {
+				b.WriteByte('1')
+			} else {
+				b.WriteByte('0')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
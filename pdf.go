@@ -0,0 +1,150 @@
+package qrstr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// ptPerMM converts millimetres to points.
+const ptPerMM = 72 / 25.4
+
+// WithPhysicalSize sets the width of the code and its quiet zone in PDF and EPS output in millimetres,
+// 50 by default.
+func WithPhysicalSize(mm float64) Option {
+	return func(q *Encoder) {
+		q.mm = mm
+	}
+}
+
+// page lays out a code with headers above it for vector output, in points from the bottom left.
+type page struct {
+	img *image.Paletted
+	// width and height of the page, and of a module
+	width, height, mod float64
+	// size and lead are the font size and line height of the headers
+	size, lead float64
+	headers    []string
+}
+
+// page lays out code and headers at the width of WithPhysicalSize.
+func (q *Encoder) page(code *image.Image, headers []string) page {
+	p := page{img: q.image(code).(*image.Paletted), headers: headers}
+	mm := q.mm
+	if mm <= 0 {
+		mm = 50
+	}
+	p.width = mm * ptPerMM
+	p.mod = p.width / float64(p.img.Bounds().Dx())
+	// Courier glyphs are 0.6 em wide, the text is sized for the longest header to fit
+	p.size = 10
+	longest := 0
+	for _, h := range headers {
+		longest = max(longest, len([]rune(h)))
+	}
+	if longest > 0 {
+		p.size = min(p.size, (p.width-2*p.mod)/(0.6*float64(longest)))
+	}
+	p.lead = p.size * 1.2
+	p.height = float64(p.img.Bounds().Dy())*p.mod + float64(len(headers))*p.lead
+	return p
+}
+
+// text calls fn with each header and the point its baseline starts at.
+func (p page) text(fn func(s string, x, y float64)) {
+	for i, h := range p.headers {
+		fn(h, p.mod, p.height-float64(i+1)*p.lead+p.size*0.2)
+	}
+}
+
+// rects calls fn with a rectangle for each run of dark modules.
+func (p page) rects(fn func(x, y, w, h float64)) {
+	dy := p.img.Bounds().Dy()
+	darkRuns(p.img, func(x, y, n int) {
+		fn(float64(x)*p.mod, float64(dy-y-1)*p.mod, float64(n)*p.mod, p.mod)
+	})
+}
+
+// EncodePDF writes data to w as a single page PDF the size of the code, at the width of
+// WithPhysicalSize, with the headers above it in Courier. The modules are vector rectangles
+// in the colours of WithColors, so they print sharp at any resolution.
+// Header characters outside Latin-1 become '?'.
+func (q *Encoder) EncodePDF(w io.Writer, data string, headers ...string) error {
+	code, err := q.code(data)
+	if err != nil {
+		return err
+	}
+	if err = q.checkOverlay(code); err != nil {
+		return err
+	}
+	return q.pdf(w, &code, q.prepare(code, headers))
+}
+
+// pdf writes the PDF of EncodePDF for code shown with headers.
+func (q *Encoder) pdf(w io.Writer, code *image.Image, headers []string) error {
+	p := q.page(code, headers)
+
+	var c bytes.Buffer
+	fg, bg := q.palette()
+	if _, _, _, a := bg.RGBA(); a != 0 {
+		fmt.Fprintf(&c, "%s rg 0 0 %.2f %.2f re f\n", rgb(bg), p.width, p.height)
+	}
+	fmt.Fprintf(&c, "%s rg\n", rgb(fg))
+	p.text(func(s string, x, y float64) {
+		fmt.Fprintf(&c, "BT /F1 %.2f Tf %.2f %.2f Td (%s) Tj ET\n", p.size, x, y, psString(s, 0xff))
+	})
+	p.rects(func(x, y, w, h float64) {
+		fmt.Fprintf(&c, "%.3f %.3f %.3f %.3f re\n", x, y, w, h)
+	})
+	c.WriteString("f\n")
+
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>", p.width, p.height),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", c.Len(), c.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	}
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// rgb returns c as three numbers from 0 to 1, the operands of PDF and PostScript colour operators.
+func rgb(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff)
+}
+
+// psString escapes s for a PDF or PostScript literal string, characters above top and
+// control characters become '?'.
+func psString(s string, top rune) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < ' ' || r >= 0x7f && r < 0xa0 || r > top:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package qrstr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const pgpBegin = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+const pgpEnd = "-----END PGP PUBLIC KEY BLOCK-----"
+
+// crc24 is the OpenPGP armor checksum from RFC 4880.
+func crc24(b []byte) uint32 {
+	crc := uint32(0xb704ce)
+	for _, c := range b {
+		crc ^= uint32(c) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= 0x1864cfb
+			}
+		}
+	}
+	return crc & 0xffffff
+}
+
+// ArmorPGPPublicKey returns the ASCII armored form of a binary OpenPGP public key.
+func ArmorPGPPublicKey(key []byte) string {
+	var b strings.Builder
+	b.WriteString(pgpBegin + "\n\n")
+	s := base64.StdEncoding.EncodeToString(key)
+	for len(s) > 64 {
+		b.WriteString(s[:64] + "\n")
+		s = s[64:]
+	}
+	b.WriteString(s + "\n")
+	c := crc24(key)
+	b.WriteString("=" + base64.StdEncoding.EncodeToString([]byte{byte(c >> 16), byte(c >> 8), byte(c)}) + "\n")
+	b.WriteString(pgpEnd + "\n")
+	return b.String()
+}
+
+var ErrPGPArmor = fmt.Errorf("text is not an armored pgp public key")
+
+// DearmorPGPPublicKey returns the binary key from an ASCII armored public key and checks its checksum.
+func DearmorPGPPublicKey(armored string) ([]byte, error) {
+	_, body, ok := strings.Cut(armored, pgpBegin)
+	if !ok {
+		return nil, ErrPGPArmor
+	}
+	body, _, ok = strings.Cut(body, pgpEnd)
+	if !ok {
+		return nil, ErrPGPArmor
+	}
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	// armor headers such as Version: end at the first blank line
+	if _, rest, ok := strings.Cut(body, "\n\n"); ok {
+		body = rest
+	}
+	var data, sum string
+	for _, l := range strings.Split(body, "\n") {
+		l = strings.TrimSpace(l)
+		if strings.HasPrefix(l, "=") {
+			sum = l[1:]
+			continue
+		}
+		data += l
+	}
+	key, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, ErrPGPArmor
+	}
+	if sum != "" {
+		c, err := base64.StdEncoding.DecodeString(sum)
+		k := crc24(key)
+		if err != nil || !bytes.Equal(c, []byte{byte(k >> 16), byte(k >> 8), byte(k)}) {
+			return nil, fmt.Errorf("pgp armor checksum does not match")
+		}
+	}
+	return key, nil
+}
+
+// EncodePGPPublicKey armors key, unless it is already armored, and renders it across as many
+// qr codes as needed to keep each part under size characters. Each code holds one part,
+// as written by Part.String, and is labeled with its number.
+// The parts are put back together with JoinPGPPublicKey.
+func (q *Encoder) EncodePGPPublicKey(key []byte, size int, headers ...string) ([]string, error) {
+	armored := string(key)
+	if !strings.Contains(armored, pgpBegin) {
+		armored = ArmorPGPPublicKey(key)
+	}
+	if size < 1 {
+		return nil, fmt.Errorf("invalid part size: %d", size)
+	}
+	parts := SplitParts(armored, (len([]rune(armored))+size-1)/size)
+	codes := make([]string, len(parts))
+	for i, p := range parts {
+		s, err := q.Encode(p.String(), append(headers[:len(headers):len(headers)], fmt.Sprintf("PGP key part %d of %d", p.Index, p.Count))...)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = s
+	}
+	return codes, nil
+}
+
+// JoinPGPPublicKey reassembles the scanned contents of the codes made by EncodePGPPublicKey,
+// in any order, and returns the armored key after checking its checksum.
+func JoinPGPPublicKey(parts ...string) (string, error) {
+	ps := make([]Part, len(parts))
+	var err error
+	for i, s := range parts {
+		if ps[i], err = ParsePart(s); err != nil {
+			return "", err
+		}
+	}
+	armored, err := JoinParts(ps...)
+	if err != nil {
+		return "", err
+	}
+	if _, err = DearmorPGPPublicKey(armored); err != nil {
+		return "", err
+	}
+	return armored, nil
+}
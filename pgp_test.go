@@ -0,0 +1,51 @@
+package qrstr
+
+import (
+	"strings"
+	"testing"
+)
+
+// testPGPKey is an ed25519 public key exported with gpg --export --armor.
+const testPGPKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mDMEatCpcBYJKwYBBAHaRw8BAQdAK6oueJA/3e4TKVbqcMMTQmujR+HWJq27D5GM
+JuNRn1u0HXFyc3RyIHRlc3QgPHRlc3RAZXhhbXBsZS5jb20+iJAEExYIADgWIQRR
+B3WAVAzfWx1UwrZ375o52mKgmgUCatCpcAIbAwULCQgHAgYVCgkICwIEFgIDAQIe
+AQIXgAAKCRB375o52mKgmnYbAQDb+4Y3WRwUeObXHxvEf8BLtCaUjvPVhzZ8ZkJ9
+ghCmJAD/ZocSAQVMfJryUnjBHewz5WIyLUe9v+A1J9pAYxi6AAg=
+=cR5y
+-----END PGP PUBLIC KEY BLOCK-----
+`
+
+func TestCRC24(t *testing.T) {
+	// the check values of CRC-24/OPENPGP
+	tests := []struct {
+		in   string
+		want uint32
+	}{
+		{"", 0xb704ce},
+		{"123456789", 0x21cf02},
+	}
+	for _, tt := range tests {
+		if got := crc24([]byte(tt.in)); got != tt.want {
+			t.Errorf("crc24(%q) = %06x, want %06x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPGPArmor(t *testing.T) {
+	key, err := DearmorPGPPublicKey(testPGPKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 230 || key[0] != 0x98 {
+		t.Fatalf("DearmorPGPPublicKey returned %d bytes starting with %#x, want a 230 byte public key packet", len(key), key[0])
+	}
+	if got := ArmorPGPPublicKey(key); got != testPGPKey {
+		t.Errorf("ArmorPGPPublicKey = %q, want the gpg armor %q", got, testPGPKey)
+	}
+	bad := strings.Replace(testPGPKey, "=cR5y", "=cR5z", 1)
+	if _, err := DearmorPGPPublicKey(bad); err == nil {
+		t.Error("DearmorPGPPublicKey accepted a wrong checksum")
+	}
+}
@@ -9,10 +9,11 @@ package qrstr
 
 import (
 	"fmt"
+	"html"
 	"image"
 	"image/color"
-	"slices"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/boombuler/barcode/qr"
 )
@@ -69,54 +70,70 @@ func (c *runeCol) addRune(s *string, top, bot color.Color) {
 var lightMode = runeCol{blank, upper, lower, whole}
 var darkMode = runeCol{whole, lower, upper, blank}
 
-// wrap wraps text around a newline, hard wrapping at the given width
-// but trying to soft wrap if possible
-func wrap(w int, s ...string) []string {
-	var line = ""
-	var lines, b []string
-	var v string
-	var i, j int
-	w--
-	for _, l := range s {
-		if len(l) < w {
-			lines = append(lines, l)
-			continue
-		}
+type Encoder struct {
+	strFunc      func(rc *runeCol, code *image.Image, headers *[]string) (string, error)
+	mode         EncoderType
+	rc           *runeCol
+	errCorr      ErrorCorrectionLevel
+	sauce        *SAUCE
+	nonce        string
+	glyphs       Glyphs
+	class        PayloadClass
+	sanitize     *string
+	inverted     bool
+	reproducible bool
+	headerPolicy HeaderPolicy
+	headerMax    int
+	frame        rune
+	padding      rune
+	gutter       Gutter
+	gutterWidth  int
+	short        func(data string) string
+	middleware   []func(next RenderFunc) RenderFunc
+	fg, bg       color.Color
+	moduleSize   int
+	forceColor   bool
+	noColor      bool
+	scale        int
+	quiet        int
+	xml          bool
+	threshold    float64
+	dither       Dither
+	overlay      string
+	depth        ColorDepth
+	resetOnce    bool
+	noBleed      bool
+	plainHeaders bool
+	mm           float64
+	sensitive    bool
+	hyphen       string
+	dir          Direction
+	align        Align
+	idPrefix     string
+	hook         ModuleHook
+	darkMode     bool
+	alt          string
+	templates    bool
+	eol          string
+	classPrefix  *string
+	bom          bool
+	metaCharset  bool
+	document     *string
+	rawHeaders   bool
+	redactKey    []byte
+}
 
-		b = strings.Split(l, " ")
-		for i, v = range b {
-			if len(v) > w {
-				for j = 0; j < len(v); j += w {
-					if j+w < len(v) {
-						lines = append(lines, v[j:j+w]+"-")
-					} else {
-						line = v[j:] + " "
-					}
-				}
-				continue
-			}
-			if len(line)+len(v) < w {
-				line += v + " "
-			} else {
-				lines = append(lines, strings.TrimSuffix(line, " "))
-				line = ""
-				continue
-			}
-			if i == len(b)-1 {
-				lines = append(lines, strings.TrimSuffix(line, " "))
-				line = ""
-				continue
-			}
-		}
-	}
+// Option changes optional settings of an Encoder, it is passed to NewEncoder.
+type Option func(q *Encoder)
 
-	return slices.Clip(lines)
-}
+// ErrDataTooLong is returned when data does not fit in the largest qr code at the error correction level.
+var ErrDataTooLong = fmt.Errorf("data is too long for a qr code at this error correction level")
 
-type Encoder struct {
-	strFunc func(rc *runeCol, code *image.Image, headers *[]string) (string, error)
-	rc      *runeCol
-	errCorr ErrorCorrectionLevel
+// tooLong reports whether err is the error boombuler/barcode/qr returns for data that does
+// not fit in a version 40 code. It has no sentinel, so this matches its message,
+// "To much data to encode", and has to follow it if the dependency changes the wording.
+func tooLong(err error) bool {
+	return err != nil && err.Error() == "To much data to encode"
 }
 
 var ErrCodeNil = fmt.Errorf("code is nil, the encoder is misconfigured, or the data is invalid")
@@ -124,129 +141,414 @@ var ErrCodeNil = fmt.Errorf("code is nil, the encoder is misconfigured, or the d
 // Encode encodes data with configuration from NewEncoder into a qr code string.
 // If headers are provided, they will be displayed above the qr code in the output.
 func (q *Encoder) Encode(data string, headers ...string) (string, error) {
-	strFunc := q.strFunc
-	if strFunc == nil {
+	if q.strFunc == nil {
 		return "", ErrCodeNil
 	}
-	var code image.Image
-	var err error
-	code, err = qr.Encode(data, qr.ErrorCorrectionLevel((*q).errCorr), qr.Auto)
+	return q.chain(q.render)(data, headers)
+}
+
+// render encodes data and renders it in the mode of the encoder, it ends the middleware chain.
+func (q *Encoder) render(data string, headers []string) (string, error) {
+	code, err := q.code(data)
+	if err != nil {
+		return "", err
+	}
+	headers = q.prepare(code, headers)
+	return q.draw(&code, &headers)
+}
+
+// draw renders code shown with headers in the mode of the encoder, wrapped in the document
+// of WithDocument if one is set.
+func (q *Encoder) draw(code *image.Image, headers *[]string) (string, error) {
+	s, err := q.strFunc(q.rc, code, headers)
+	if err != nil || q.document == nil || q.mode.ext() != ".html" {
+		return s, err
+	}
+	return q.wrap(s, *code, *headers), nil
+}
+
+// code returns the qr code image for data, one pixel per module with no quiet zone.
+func (q *Encoder) code(data string) (image.Image, error) {
+	c := q.class
+	if c == PayloadAuto {
+		c = AnalyzePayload(data)
+	}
+	code, err := qr.Encode(data, qr.ErrorCorrectionLevel((*q).errCorr), c.encoding())
+	if tooLong(err) {
+		return nil, ErrDataTooLong
+	}
+	if err == nil && q.mode.raster() {
+		if err = q.checkOverlay(code); err != nil {
+			return nil, err
+		}
+	}
+	if err == nil && q.hooked() && !q.hookFits(code) {
+		return nil, ErrTooManySkipped
+	}
+	if err != nil {
+		return nil, err
+	}
+	l := labeled{code, "", data}
+	if q.short != nil {
+		l.label = q.short(data)
+	}
+	return l, nil
+}
+
+func (q *Encoder) text(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	var output strings.Builder
+	eol := q.lineEnd("\n")
+	err := q.textLines(rc, code, headers, func(line string) error {
+		output.WriteString(line + eol)
+		return nil
+	})
 	if err != nil {
 		return "", err
 	}
-	return strFunc(q.rc, &code, &headers)
+	return output.String(), nil
 }
 
-func text(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+// textRows returns the lines of text renders the code as, without their newlines.
+func (q *Encoder) textRows(rc *runeCol, code *image.Image, headers *[]string) ([]string, error) {
+	var rows []string
+	err := q.textLines(rc, code, headers, func(line string) error {
+		rows = append(rows, line)
+		return nil
+	})
+	return rows, err
+}
+
+// textLines renders the code as text with the encoder's glyphs and calls emit with each line,
+// without its newline. It stops at the first error from emit.
+func (q *Encoder) textLines(rc *runeCol, code *image.Image, headers *[]string, emit func(line string) error) error {
 	if rc == nil || code == nil {
-		return "", ErrCodeNil
+		return ErrCodeNil
 	}
-	var output = ""
-	dx := (*code).Bounds().Dx()
-	dy := (*code).Bounds().Dy()
-	wr := rc.getRune(color.White, color.White)
-	prefix := string(wr)
-	suffix := string(wr) + "\n"
+	g := q.glyphs.set()
+	wr := g.glyph(rc, 0)
+	qw := utf8.RuneCountInString(wr)
+	// inner is the width in columns of the code and its quiet zone
+	inner := ((*code).Bounds().Dx()+g.w-1)/g.w*qw + 2*qw
+	d := inner - 2
+	if q.padding != 0 {
+		wr = strings.Repeat(string(q.padding), qw)
+	}
+	side, top, bottom := string(whole), upper, lower
+	if q.frame != 0 {
+		side, top, bottom = string(q.frame), q.frame, q.frame
+	}
+	prefix := wr
+	suffix := wr
 
 	hashead := headers != nil && len(*headers) > 0
 
+	if hashead && q.gutter != GutterNone {
+		cl := []string{strings.Repeat(wr, inner/qw)}
+		for y := 0; y < g.rows(*code); y++ {
+			cl = append(cl, wr+g.row(rc, *code, y)+wr)
+		}
+		cl = append(cl, cl[0])
+		if l := label(code); l != "" {
+			cl = append(cl, labelLine(cl[0], l))
+		}
+		if ok, err := q.gutterLines(cl, *headers, emit); ok {
+			return err
+		}
+	}
+	if hashead && q.plainHeaders {
+		hl, err := q.headerLines(inner, *headers)
+		if err != nil {
+			return err
+		}
+		for _, v := range hl {
+			if err := emit(v); err != nil {
+				return err
+			}
+		}
+		hashead = false
+	}
+	var lines []string
 	if hashead {
-		output += fmt.Sprintln(string(whole) + pad(dx+2, upper) + string(whole))
-		for _, v := range wrap(dx, *headers...) {
-			v = v + pad(dx-len(v)+1, blank) + string(whole)
-			v = string(whole) + string(blank) + v
-			output += v + "\n"
+		lines = append(lines, side+pad(inner, top)+side)
+		hl, err := q.headerLines(d, *headers)
+		if err != nil {
+			return err
 		}
-
-		output += string(whole) + pad(dx+2, lower) + string(whole) + "\n" + string(whole) + pad(dx+2, wr) + string(whole) + "\n"
-		prefix = string(whole) + string(wr)
-		suffix = string(wr) + string(whole) + "\n"
+		for _, v := range hl {
+			lines = append(lines, side+string(blank)+v+pad(d-DisplayWidth(v)+1, blank)+side)
+		}
+		lines = append(lines, side+pad(inner, bottom)+side)
+		lines = append(lines, side+strings.Repeat(wr, inner/qw)+side)
+		prefix = side + wr
+		suffix = wr + side
 	} else {
-		output += pad(dx+2, wr) + "\n"
+		lines = append(lines, strings.Repeat(wr, inner/qw))
 	}
-
-	output += prefix
-	prefix = suffix + prefix
-
-	var y, x int
-	for y = 0; y < dy-dy%2; y += 2 {
-		for x = 0; x < dx; x++ {
-			rc.addRune(&output, (*code).At(x, y), (*code).At(x, y+1))
+	for _, l := range lines {
+		if err := emit(l); err != nil {
+			return err
 		}
-		output += prefix
 	}
-	if dy%2 == 1 {
-		for x = 0; x < dx; x++ {
-			rc.addRune(&output, (*code).At(x, y), color.White)
+	for y := 0; y < g.rows(*code); y++ {
+		if err := emit(prefix + g.row(rc, *code, y) + suffix); err != nil {
+			return err
 		}
-		output += suffix
-	} else {
-		output = strings.TrimSuffix(output, prefix) + suffix
 	}
-
+	last := strings.Repeat(wr, inner/qw)
 	if hashead {
-		output += pad(dx+4, wr) + "\n"
-	} else {
-		output += pad(dx+2, wr) + "\n"
+		last = strings.Repeat(wr, (inner+2)/qw)
 	}
-
-	return output, nil
+	if err := emit(last); err != nil {
+		return err
+	}
+	if l := label(code); l != "" {
+		return emit(labelLine(last, l))
+	}
+	return nil
 }
 
 var ErrHeadersNotSupported = fmt.Errorf("headers are not supported in this mode")
 
-func svg(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+func (q *Encoder) svg(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
 	if headers != nil && len(*headers) > 0 {
 		return "", ErrHeadersNotSupported
 	}
 	if code == nil {
 		return "", ErrCodeNil
 	}
+	return q.svgImage(code, q.altText(code, nil), ` id="`+q.codeID(*code, nil)+`"`), nil
+}
+
+// svgImage draws code as an SVG image described to screen readers by alt, attrs are added to the svg element.
+func (q *Encoder) svgImage(code *image.Image, alt, attrs string) string {
 	var output string
 	dx := (*code).Bounds().Dx()
 	dy := (*code).Bounds().Dy()
-	output = fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0.5 %d %d">`, dx, dy)
-	output += fmt.Sprintf(`<rect x="0" y="0.5" width="%d" height="%d" fill="white"></rect>`, dx, dy)
-	fln := func(c color.Color, x, y int) string {
-		if c == color.Black {
-			return fmt.Sprintf("H%d", x)
+	fg, bg := q.colors()
+	l := label(code)
+	h := dy
+	if l != "" {
+		h += 4
+	}
+	// crisp edges stop anti-aliasing blurring the seams between modules
+	size := ""
+	if q.mode == SVGMode && q.moduleSize > 0 {
+		size = fmt.Sprintf(` width="%d" height="%d"`, dx*q.moduleSize, h*q.moduleSize)
+	}
+	output = fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0.5 %d %d"%s shape-rendering="crispEdges" role="img" aria-label="%s"%s>`,
+		dx, h, size, html.EscapeString(alt), attrs)
+	output += fmt.Sprintf(`<rect x="0" y="0.5" width="%d" height="%d"%s></rect>`, dx, h, q.svgPaint("fill", bg))
+	if q.hooked() {
+		output += q.svgModules(*code, fg)
+	} else {
+		fln := func(c color.Color, x, y int) string {
+			if c == color.Black {
+				return fmt.Sprintf("H%d", x)
+			}
+			return fmt.Sprintf("M%d,%d", x, y+1)
 		}
-		return fmt.Sprintf("M%d,%d", x, y+1)
-	}
-	var path string
-	var c color.Color
-	for y := 0; y < dy; y++ {
-		path += fmt.Sprintf("M0,%d", y+1)
-		c = (*code).At(0, y)
-		for x := 1; x < dx; x++ {
-			if (*code).At(x, y) == c {
-				continue
+		var path string
+		var c color.Color
+		for y := 0; y < dy; y++ {
+			path += fmt.Sprintf("M0,%d", y+1)
+			c = (*code).At(0, y)
+			for x := 1; x < dx; x++ {
+				if (*code).At(x, y) == c {
+					continue
+				}
+				path += fln(c, x, y)
+				c = (*code).At(x, y)
 			}
-			path += fln(c, x, y)
-			c = (*code).At(x, y)
+			path += fln(c, dx, y)
 		}
-		path += fln(c, dx, y)
+		output += fmt.Sprintf(`<path d="%s" stroke-width="1"%s></path>`, path, q.svgPaint("stroke", fg))
 	}
-	output += fmt.Sprintf(`<path d="%s" stroke-width="1" stroke="black"></path>`, path)
-	return output + "</svg>", nil
+	if l != "" {
+		output += q.svgLabel(l, dx, dy, fg)
+	}
+	return output + "</svg>"
 }
 
-func html(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+// htmlStyle is the style of the div around HTML mode codes, formatted with its width,
+// background colour and text colour.
+const htmlStyle = `font-family: monospace;%smax-width:calc( 100%% - 2em );padding: 0 1em 1em 1em;background: %s; color: %[3]s;border:1em solid %[3]s;`
+
+func (q *Encoder) html(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
 	if code == nil {
 		return "", ErrCodeNil
 	}
-	var output = fmt.Sprintf(`<div class="qr" style="font-family: monospace;width: %dem;max-width:calc( 100%% - 2em );padding: 0 1em 1em 1em;background: white; color: black;border:1em solid black;">%c`, (*code).Bounds().Dx()+1, '\n')
+	return q.htmlCode(code, headers, htmlFlags{}), nil
+}
+
+// htmlFlags change how the div of HTMLMode, HTMLSVGMode and HTMLGridMode is drawn.
+type htmlFlags struct {
+	// shared leaves out the <style> elements and styles by class only, for the style sheet of HTMLStyle.
+	shared bool
+	// escape escapes the headers even with WithRawHTMLHeaders.
+	escape bool
+}
+
+// htmlCode returns the div of HTMLMode and HTMLSVGMode.
+func (q *Encoder) htmlCode(code *image.Image, headers *[]string, f htmlFlags) string {
+	id := q.codeID(*code, deref(headers))
+	output := q.htmlOpen((*code).Bounds().Dx()+1, id, headers, "", f)
+	alt := q.altText(code, headers)
+	return output + q.svgImage(code, alt, "") + q.fallback(alt, headers, f.shared) + "</div>"
+}
+
+// darkStyle turns the div around HTML codes dark on dark pages, formatted with the dark and light
+// module colours. The code keeps its light margin, so it still has dark modules on light.
+// It needs !important to win over the inline style of the div.
+const darkStyle = `@media (prefers-color-scheme: dark){.qr{background:%[1]s !important;color:%[2]s !important;border-color:%[1]s !important;}` +
+	`.qr>svg,.qr>.qr-grid{padding:1em;background:%[2]s;}}`
+
+// htmlOpen returns the opening of the div around HTML codes w em wide with the given id, followed
+// by the headers. style holds <style> elements to go with it, they are put before the div, or inside it for WithXML.
+func (q *Encoder) htmlOpen(w int, id string, headers *[]string, style string, f htmlFlags) string {
+	var open string
+	fg, bg := q.colors()
+	xmlns := ""
+	if q.xml {
+		xmlns = ` xmlns="http://www.w3.org/1999/xhtml"`
+	}
+	align := ""
+	if q.align != AlignStart {
+		align = "text-align: " + q.align.String() + ";"
+	}
+	if q.nonce == "" && !f.shared {
+		open = `<div class="` + q.prefix() + `" id="` + id + `"` + xmlns + q.dirAttr() + ` style="` + fmt.Sprintf(htmlStyle, fmt.Sprintf("width: %dem;", w)+align, bg, fg) + "\">\n"
+	} else {
+		// the width and alignment are in classes named after them, so codes on one page don't clash
+		p := q.prefix()
+		class := fmt.Sprintf("%s %s-%d", p, p, w)
+		rules := fmt.Sprintf(".qr-%d{width: %dem;}", w, w)
+		if align != "" {
+			class += " " + p + "-" + q.align.String()
+			rules += ".qr-" + q.align.String() + "{" + align + "}"
+		}
+		if !f.shared {
+			style += fmt.Sprintf(`<style nonce="%s">%s</style>%c`, html.EscapeString(q.nonce),
+				q.scope(fmt.Sprintf(".qr{%s}.qr-sr{%s}", fmt.Sprintf(htmlStyle, "", bg, fg), srOnly)+rules+q.svgRules()), '\n')
+		}
+		open = fmt.Sprintf(`<div class="%s" id="%s"%s%s>%c`, class, id, xmlns, q.dirAttr(), '\n')
+	}
+	if q.darkMode && !f.shared {
+		nonce := ""
+		if q.nonce != "" {
+			nonce = ` nonce="` + html.EscapeString(q.nonce) + `"`
+		}
+		style += fmt.Sprintf("<style%s>%s</style>\n", nonce, q.scope(fmt.Sprintf(darkStyle, fg, bg)))
+	}
+	output := style + open
+	if q.xml {
+		// one root element, so the output is an XML document
+		output = open + style
+	}
 	if headers != nil && len(*headers) > 0 {
-		for _, v := range *headers {
-			output += "<p>" + v + "</p>\n"
+		for i, v := range *headers {
+			if !q.rawHeaders || q.xml || f.escape {
+				v = html.EscapeString(v)
+			}
+			output += q.headerOpen(headerID(id, i)) + v + "</p>\n"
 		}
 	}
-	s, _ := svg(rc, code, nil)
-	if s == "" {
-		return "", ErrCodeNil
+	return output
+}
+
+// WithXML makes SVG and HTML output well formed XML with a single root element, that
+// encoding/xml and other XML tools can parse: headers are escaped even with WithRawHTMLHeaders,
+// <style> elements go inside the div and the div gets the XHTML namespace. Empty elements keep
+// their closing tags, so the markup stays valid HTML too.
+func WithXML() Option {
+	return func(q *Encoder) {
+		q.xml = true
+	}
+}
+
+// WithRawHTMLHeaders puts headers in HTMLMode, HTMLSVGMode and HTMLGridMode output as markup
+// instead of escaping them, for callers that pass links or formatting on purpose.
+// Headers must then never come from untrusted input.
+func WithRawHTMLHeaders() Option {
+	return func(q *Encoder) {
+		q.rawHeaders = true
+	}
+}
+
+// WithNonce moves the inline style of HTMLMode output into a <style> element with the given
+// nonce attribute, so it is allowed by a strict Content-Security-Policy style-src.
+func WithNonce(nonce string) Option {
+	return func(q *Encoder) {
+		q.nonce = nonce
+	}
+}
+
+// WithDarkMode adds a prefers-color-scheme media query to HTMLMode, HTMLGridMode and HTMLSVGMode
+// output that turns the div around the code dark on pages in dark mode. The code itself keeps
+// dark modules on a light margin, as readers expect.
+func WithDarkMode() Option {
+	return func(q *Encoder) {
+		q.darkMode = true
+	}
+}
+
+// WithInverted draws dark modules white on black instead of black on white, for readers
+// that need light on dark codes. It changes SVG, HTML and image output, text modes already
+// come in both polarities as TextDarkMode and TextLightMode.
+// Not every reader can scan inverted codes, Lint warns about it.
+func WithInverted() Option {
+	return func(q *Encoder) {
+		q.inverted = true
+	}
+}
+
+// colors returns the css colours of dark and light modules.
+func (q *Encoder) colors() (fg, bg string) {
+	f, b := q.palette()
+	if q.mode == HTMLSVGMode {
+		return "var(--qr-fg, " + cssColor(f) + ")", "var(--qr-bg, " + cssColor(b) + ")"
+	}
+	return cssColor(f), cssColor(b)
+}
+
+// svgPaint returns the attribute painting an SVG element in colour c, which goes in a style
+// attribute if it is a CSS variable, as presentation attributes cannot hold them. With a nonce
+// it is a class instead, styled by svgRules, as a nonce does not allow style attributes.
+func (q *Encoder) svgPaint(prop, c string) string {
+	if !strings.HasPrefix(c, "var(") {
+		return " " + prop + `="` + c + `"`
+	}
+	if q.nonce != "" {
+		which := "bg"
+		if strings.HasPrefix(c, "var(--qr-fg") {
+			which = "fg"
+		}
+		return ` class="` + q.prefix() + "-" + prop + "-" + which + `"`
+	}
+	return ` style="` + prop + ": " + c + `"`
+}
+
+// svgRules returns the rules of the classes svgPaint uses with a nonce, with the default class names.
+func (q *Encoder) svgRules() string {
+	if q.mode != HTMLSVGMode || q.nonce == "" {
+		return ""
 	}
-	output += s + "</div>"
-	return output, nil
+	fg, bg := q.colors()
+	return fmt.Sprintf(".qr-fill-fg{fill: %s;}.qr-fill-bg{fill: %s;}.qr-stroke-fg{stroke: %s;}", fg, bg, fg)
+}
+
+// palette returns the colours of dark and light modules.
+func (q *Encoder) palette() (fg, bg color.Color) {
+	fg, bg = color.Black, color.White
+	if q.fg != nil {
+		fg = q.fg
+	}
+	if q.bg != nil {
+		bg = q.bg
+	}
+	if q.inverted {
+		return bg, fg
+	}
+	return fg, bg
 }
 
 type EncoderType int
@@ -268,7 +570,8 @@ const (
 	// Returns a div with headers and an SVG image of the qr code.
 	HTMLMode EncoderType = 2
 	// TerminalMode makes qr codes for printing on xterm terminals with auto color.
-	// Colours are set automatically with this mode.
+	// Colours are set automatically with this mode. If standard output is not a terminal
+	// the colour escapes are left out, unless WithForceColor is given.
 	// MUST BE PRINTED/DISPLAYED USING A MONOSPACE FONT.
 	TerminalMode EncoderType = 3
 	// SVGMode makes an SVG image of the qr code.
@@ -276,6 +579,82 @@ const (
 	// It can also be saved to a file with a .svg extension.
 	// Does not implement headers, if any are provided, an error will be returned.
 	SVGMode EncoderType = 4
+	// ANSIMode makes a classic .ans art file using CP437 bytes and SGR colours,
+	// for BBSes and retro terminal art tools. The output is not valid UTF-8.
+	// Lines end with CRLF. A SAUCE record is appended if WithSAUCE is given.
+	ANSIMode EncoderType = 5
+	// HTMLGridMode makes qr codes for HTML documents as a CSS grid of empty elements,
+	// one per module, inside the same div as HTMLMode. The modules are styled by a
+	// <style> element, which gets the nonce of WithNonce if one is set.
+	HTMLGridMode EncoderType = 6
+	// BrailleMode makes very compact qr codes for terminals from braille patterns, eight modules
+	// to a character, like TextDarkMode with the Braille glyphs.
+	// MUST BE PRINTED/DISPLAYED USING A MONOSPACE FONT WITH BRAILLE PATTERNS.
+	BrailleMode EncoderType = 7
+	// SixelMode makes a DEC sixel image of the qr code for terminals that show bitmaps,
+	// like xterm, mlterm and foot. Each module is 4 pixels, or the scale of WithScale,
+	// in the colours of WithColors with the quiet zone of images. Headers are printed above it.
+	SixelMode EncoderType = 8
+	// KittyMode shows the qr code as a PNG with the kitty graphics protocol, for kitty, WezTerm
+	// and Ghostty. Each module is 4 pixels, or the scale of WithScale, in the colours of
+	// WithColors with the quiet zone of images. Headers are printed above it.
+	KittyMode EncoderType = 9
+	// ITermMode shows the qr code as a PNG with the iTerm2 inline image escape, OSC 1337 File.
+	// Each module is 4 pixels, or the scale of WithScale, in the colours of WithColors
+	// with the quiet zone of images. Headers are printed above it.
+	ITermMode EncoderType = 10
+	// CP437Mode makes qr codes like TextDarkMode as raw code page 437 bytes with CRLF line ends,
+	// for DOS machines, serial terminals and text displays that only know the OEM code page.
+	// Header characters missing from the code page become '?'. The output is not valid UTF-8.
+	CP437Mode EncoderType = 11
+	// EPSMode makes an Encapsulated PostScript file of the qr code for print layouts, vector
+	// rectangles at the width of WithPhysicalSize in the colours of WithColors, with the quiet zone
+	// of images. Headers are set above it in Courier, characters outside ASCII become '?'.
+	EPSMode EncoderType = 12
+	// ZPLMode makes a ZPL II label for Zebra thermal printers, the modules drawn as filled
+	// boxes 4 dots wide, or the scale of WithScale, with the quiet zone of images.
+	// Headers are printed above the code as text fields.
+	ZPLMode EncoderType = 13
+	// ESCPOSMode makes ESC/POS commands for receipt printers, the code as a raster bit image
+	// with 4 dots per module, or the scale of WithScale, and the quiet zone of images, centred
+	// and fed past the tear bar. Headers are printed above it in code page 437.
+	// The output is binary.
+	ESCPOSMode EncoderType = 14
+	// TSPLMode makes a TSPL label for TSC printers, and EPLMode an EPL2 label for older Zebra and
+	// Eltron printers, like ZPLMode: a filled bar for each run of dark modules, 4 dots per module
+	// or the scale of WithScale, with the quiet zone of images. Headers are printed above the
+	// code in code page 437.
+	TSPLMode EncoderType = 15
+	EPLMode  EncoderType = 16
+	// PBMMode makes a plain PBM bitmap of the qr code, one pixel per module or the scale of
+	// WithScale, with the quiet zone of images, for shell pipelines and e-ink firmware.
+	// Does not implement headers, if any are provided, an error will be returned.
+	PBMMode EncoderType = 17
+	// TikZMode makes a TikZ picture of the qr code for LaTeX documents, one filled rectangle per
+	// run of dark modules, at the width of WithPhysicalSize in the colours of WithColors, with the
+	// quiet zone of images. Headers are set above it. Needs \usepackage{tikz}.
+	TikZMode EncoderType = 18
+	// HTMLImageMode makes a single <img> tag holding the qr code as a PNG data URI, 4 pixels per
+	// module or the scale of WithScale, with the quiet zone of images. Headers become its alt
+	// text. It is the smallest HTML output, for emails and pages that cannot use SVG.
+	HTMLImageMode EncoderType = 19
+	// HTMLSVGMode makes qr codes for HTML documents like HTMLMode, with the colours taken from the
+	// CSS custom properties --qr-fg and --qr-bg, which default to those of WithColors. Pages can
+	// set them, in a prefers-color-scheme media query for example, to restyle every code.
+	HTMLSVGMode EncoderType = 20
+	// HTMLFragmentMode makes only the grid of HTMLGridMode, without a container, headers or
+	// styles, for templates and components that wrap it themselves. GetCSS returns its styles.
+	// The code needs a light margin of four modules around it to scan.
+	// Does not implement headers, if any are provided, an error will be returned.
+	HTMLFragmentMode EncoderType = 21
+	// HTMLCanvasMode makes a <canvas> holding the modules packed in an attribute and a small
+	// script that draws them, 4 pixels per module or the scale of WithScale, with the quiet zone
+	// of images. Headers become its label. The script gets the nonce of WithNonce if one is set.
+	HTMLCanvasMode EncoderType = 22
+	// HTMLTableMode makes qr codes for HTML emails as a table with inline styles on every cell,
+	// 4 pixels per module or the scale of WithScale, with the quiet zone of images and the headers
+	// in rows above. It has no <style> element or classes, which Gmail and Outlook strip.
+	HTMLTableMode EncoderType = 23
 
 	// ErrorCorrection7Percent indicates 7% of lost data can be recovered, makes the qr code smaller
 	ErrorCorrection7Percent ErrorCorrectionLevel = 0
@@ -292,36 +671,103 @@ const (
 // The error correction level determines the amount of data that can be recovered from the qr code.
 // The encoder type must be one of the following: TextDarkMode, TextLightMode, HTMLMode
 // The error correction level must be one of the following: ErrorCorrection7Percent, ErrorCorrection15Percent, ErrorCorrection25Percent, ErrorCorrection30Percent
-func NewEncoder(encoderType EncoderType, errorCorrectionLevel ErrorCorrectionLevel) (*Encoder, error) {
+// Any options are applied after the type and error correction level.
+func NewEncoder(encoderType EncoderType, errorCorrectionLevel ErrorCorrectionLevel, opts ...Option) (*Encoder, error) {
 	var q Encoder
+	q.mode = encoderType
 	switch encoderType {
 	case TextDarkMode:
 		q.rc = &darkMode
-		q.strFunc = text
+		q.strFunc = q.text
 		break
 	case TextLightMode:
 		q.rc = &lightMode
-		q.strFunc = text
+		q.strFunc = q.text
 		break
 	case HTMLMode:
-		q.strFunc = html
+		q.strFunc = q.html
 		break
 	case SVGMode:
-		q.strFunc = svg
+		q.strFunc = q.svg
 		break
 	case TerminalMode:
 		q.rc = &darkMode
 		q.strFunc = func(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
-			s, e := text(rc, code, headers)
+			if !q.escapes() {
+				return q.text(rc, code, headers)
+			}
+			lines, e := q.textRows(rc, code, headers)
 			if e != nil {
 				return "", e
 			}
-			front := "\033[40;97m"
-			back := "\033[0m\n"
-			s = strings.ReplaceAll(s, "\n", back+front)
-			return front + strings.TrimSuffix(s, front), nil
+			eol := q.lineEnd("\n")
+			for i, l := range lines {
+				lines[i] = q.termLine(l, i == 0, i == len(lines)-1)
+			}
+			return strings.Join(lines, eol) + eol, nil
 		}
 		break
+	case ANSIMode:
+		q.rc = &darkMode
+		q.strFunc = q.ansi
+		break
+	case HTMLGridMode:
+		q.strFunc = q.htmlGrid
+		break
+	case BrailleMode:
+		q.rc = &darkMode
+		q.glyphs = Braille
+		q.strFunc = q.text
+		break
+	case SixelMode:
+		q.strFunc = q.sixel
+		break
+	case KittyMode:
+		q.strFunc = q.kitty
+		break
+	case ITermMode:
+		q.strFunc = q.iterm
+		break
+	case CP437Mode:
+		q.rc = &darkMode
+		q.strFunc = q.cp437
+		break
+	case EPSMode:
+		q.strFunc = q.eps
+		break
+	case ZPLMode:
+		q.strFunc = q.zpl
+		break
+	case ESCPOSMode:
+		q.strFunc = q.escpos
+		break
+	case TSPLMode:
+		q.strFunc = q.tspl
+		break
+	case EPLMode:
+		q.strFunc = q.epl
+		break
+	case PBMMode:
+		q.strFunc = q.pbm
+		break
+	case TikZMode:
+		q.strFunc = q.tikz
+		break
+	case HTMLImageMode:
+		q.strFunc = q.htmlImage
+		break
+	case HTMLSVGMode:
+		q.strFunc = q.html
+		break
+	case HTMLFragmentMode:
+		q.strFunc = q.htmlFragment
+		break
+	case HTMLCanvasMode:
+		q.strFunc = q.canvas
+		break
+	case HTMLTableMode:
+		q.strFunc = q.htmlTable
+		break
 	default:
 		return nil, fmt.Errorf("invalid encoder type: %d", encoderType)
 	}
@@ -329,5 +775,10 @@ func NewEncoder(encoderType EncoderType, errorCorrectionLevel ErrorCorrectionLev
 		return nil, fmt.Errorf("invalid error correction level: %d", errorCorrectionLevel)
 	}
 	q.errCorr = errorCorrectionLevel
+	q.quiet = quietZone
+	q.hyphen = "-"
+	for _, opt := range opts {
+		opt(&q)
+	}
 	return &q, nil
 }
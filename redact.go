@@ -0,0 +1,60 @@
+package qrstr
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// redactKey keys Redact, it is made when the process starts so hashes cannot be looked up
+// in a table of likely payloads.
+var redactKey = func() []byte {
+	k := make([]byte, 32)
+	rand.Read(k)
+	return k
+}()
+
+// WithSensitive marks payloads as secret, like one time password seeds or WiFi passwords.
+// Manifests, file names of WriteBatch, Go source comments of EncodeGo and Redacted then show
+// a hash of the payload instead of the payload. The codes themselves still hold it.
+func WithSensitive() Option {
+	return func(q *Encoder) {
+		q.sensitive = true
+	}
+}
+
+// WithRedactKey sets the key Redacted hashes payloads with, instead of the key of the process,
+// so hashes can be matched across runs and machines that share it. Keep the key secret, short
+// payloads like PINs can be found from their hash by anyone who has it.
+func WithRedactKey(key []byte) Option {
+	return func(q *Encoder) {
+		q.redactKey = key
+	}
+}
+
+// Redact returns a short HMAC-SHA256 of data, like hmac:0123abcd..., that tells payloads
+// apart in logs without revealing them. The key is made at random when the process starts,
+// so the same payload gives the same hash only within one process, see WithRedactKey.
+func Redact(data string) string {
+	return redact(redactKey, data)
+}
+
+// redact returns the short HMAC-SHA256 of data keyed with key.
+func redact(key []byte, data string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return "hmac:" + hex.EncodeToString(m.Sum(nil)[:8])
+}
+
+// Redacted returns data, or its hash like Redact if the encoder has WithSensitive,
+// for middleware and callers logging payloads. The hash is keyed with WithRedactKey if set.
+func (q *Encoder) Redacted(data string) string {
+	if !q.sensitive {
+		return data
+	}
+	if q.redactKey != nil {
+		return redact(q.redactKey, data)
+	}
+	return Redact(data)
+}
@@ -0,0 +1,56 @@
+package qrstr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Redrawer prints codes over the one printed before on a terminal, for countdowns and
+// displays that refresh in place. Lines wider than the terminal wrap and throw off the count
+// of lines to go back over, so keep codes narrower than the terminal.
+type Redrawer struct {
+	w     io.Writer
+	drawn bool
+	// last is the number of newlines in the previous output
+	last int
+}
+
+// NewRedrawer returns a Redrawer printing to w.
+func NewRedrawer(w io.Writer) *Redrawer {
+	return &Redrawer{w: w}
+}
+
+// Draw moves the cursor up over the previous output, clears it, and prints s.
+func (r *Redrawer) Draw(s string) error {
+	if err := r.Clear(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(r.w, s)
+	r.drawn = true
+	r.last = strings.Count(s, "\n")
+	return err
+}
+
+// Clear removes the previous output and leaves the cursor where it started.
+func (r *Redrawer) Clear() error {
+	var err error
+	switch {
+	case !r.drawn:
+	case r.last == 0:
+		_, err = io.WriteString(r.w, "\r\033[J")
+	default:
+		_, err = fmt.Fprintf(r.w, "\r\033[%dA\033[J", r.last)
+	}
+	r.drawn, r.last = false, 0
+	return err
+}
+
+// Encode encodes data with q and draws it over the previous output.
+func (r *Redrawer) Encode(q *Encoder, data string, headers ...string) error {
+	s, err := q.Encode(data, headers...)
+	if err != nil {
+		return err
+	}
+	return r.Draw(s)
+}
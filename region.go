@@ -0,0 +1,96 @@
+package qrstr
+
+import "fmt"
+
+// Region is the part of a code a module belongs to. Readers find and read a code by its function
+// patterns, everything but RegionData, so drawings over a code should leave them alone.
+type Region int
+
+const (
+	// RegionData holds the encoded data and error correction codewords, and the remainder bits.
+	RegionData Region = 0
+	// RegionFinder is the three square finder patterns in the corners and the light separators around them.
+	RegionFinder Region = 1
+	// RegionTiming is the row and column of alternating modules between the finder patterns.
+	RegionTiming Region = 2
+	// RegionAlignment is the small square alignment patterns of version 2 and up.
+	RegionAlignment Region = 3
+	// RegionFormat holds the error correction level and mask, beside the finder patterns,
+	// and the dark module above the bottom left one.
+	RegionFormat Region = 4
+	// RegionVersion holds the version in two 6x3 blocks, from version 7 up.
+	RegionVersion Region = 5
+)
+
+// String returns the name of the region, like finder.
+func (r Region) String() string {
+	switch r {
+	case RegionData:
+		return "data"
+	case RegionFinder:
+		return "finder"
+	case RegionTiming:
+		return "timing"
+	case RegionAlignment:
+		return "alignment"
+	case RegionFormat:
+		return "format"
+	case RegionVersion:
+		return "version"
+	}
+	return fmt.Sprintf("Region(%d)", int(r))
+}
+
+// Region returns the region of the module in column x of row y, RegionData outside the code.
+func (m Matrix) Region(x, y int) Region {
+	if x < 0 || y < 0 || x >= m.Size() || y >= m.Size() {
+		return RegionData
+	}
+	return regionAt(x, y, m.Size())
+}
+
+// regionAt returns the region of module x, y of a code size modules wide.
+func regionAt(x, y, size int) Region {
+	version := versionOf(size)
+	switch {
+	case (x < 8 || x >= size-8) && y < 8 || x < 8 && y >= size-8:
+		return RegionFinder
+	case x == 8 && (y <= 8 || y >= size-8) || y == 8 && (x <= 8 || x >= size-8):
+		return RegionFormat
+	case version >= 7 && (x >= size-11 && x < size-8 && y < 6 || y >= size-11 && y < size-8 && x < 6):
+		return RegionVersion
+	}
+	centers := alignmentCenters(version)
+	for i, cy := range centers {
+		for j, cx := range centers {
+			// the patterns that would sit on the finder patterns are left out
+			if i == 0 && j == 0 || i == 0 && j == len(centers)-1 || i == len(centers)-1 && j == 0 {
+				continue
+			}
+			if x >= cx-2 && x <= cx+2 && y >= cy-2 && y <= cy+2 {
+				return RegionAlignment
+			}
+		}
+	}
+	// alignment patterns on the timing patterns take their modules
+	if x == 6 || y == 6 {
+		return RegionTiming
+	}
+	return RegionData
+}
+
+// alignmentCenters returns the rows and columns the alignment patterns of a version are centred on.
+func alignmentCenters(version int) []int {
+	if version < 2 {
+		return nil
+	}
+	n := version/7 + 2
+	size := version*4 + 17
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	c := make([]int, n)
+	c[0] = 6
+	for i, pos := n-1, size-7; i > 0; i, pos = i-1, pos-step {
+		c[i] = pos
+	}
+	return c
+}
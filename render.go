@@ -0,0 +1,134 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// Dither selects how RenderImage turns shades of grey into dark and light modules.
+type Dither int
+
+const (
+	// DitherNone makes every pixel darker than the threshold dark. Default.
+	DitherNone Dither = 0
+	// DitherOrdered compares pixels against a 4x4 Bayer matrix around the threshold,
+	// giving an even cross hatch that suits gradients and small glyphs.
+	DitherOrdered Dither = 1
+	// DitherFloydSteinberg spreads the error of each pixel to its neighbours,
+	// which keeps the most detail in photos.
+	DitherFloydSteinberg Dither = 2
+)
+
+// String returns the name of the dithering, like floyd-steinberg.
+func (d Dither) String() string {
+	switch d {
+	case DitherNone:
+		return "none"
+	case DitherOrdered:
+		return "ordered"
+	case DitherFloydSteinberg:
+		return "floyd-steinberg"
+	}
+	return fmt.Sprintf("Dither(%d)", int(d))
+}
+
+// bayer is the 4x4 ordered dithering matrix.
+var bayer = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// WithThreshold sets the brightness from 0 to 1 below which RenderImage draws pixels dark.
+// The default is 0.5.
+func WithThreshold(t float64) Option {
+	return func(q *Encoder) {
+		q.threshold = t
+	}
+}
+
+// WithDither sets how RenderImage draws shades of grey.
+func WithDither(d Dither) Option {
+	return func(q *Encoder) {
+		q.dither = d
+	}
+}
+
+// monochrome returns img as black and white with its bounds at 0, 0, pixels darker than t
+// are black after dithering with d. Transparent pixels are light.
+func monochrome(img image.Image, t float64, d Dither) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	v := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			if _, _, _, a := c.RGBA(); a < 0x8000 {
+				v[y*w+x] = 1
+			} else {
+				v[y*w+x] = luma(c)
+			}
+		}
+	}
+	out := image.NewPaletted(image.Rect(0, 0, w, h), color.Palette{color.White, color.Black})
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := v[y*w+x]
+			cut := t
+			if d == DitherOrdered {
+				cut += (bayer[y%4][x%4]+0.5)/16 - 0.5
+			}
+			dark := p < cut
+			if dark {
+				out.SetColorIndex(x, y, 1)
+			}
+			if d != DitherFloydSteinberg {
+				continue
+			}
+			e := p
+			if !dark {
+				e--
+			}
+			spread := func(dx, dy int, f float64) {
+				if x+dx >= 0 && x+dx < w && y+dy < h {
+					v[(y+dy)*w+x+dx] += e * f
+				}
+			}
+			spread(1, 0, 7.0/16)
+			spread(-1, 1, 3.0/16)
+			spread(0, 1, 5.0/16)
+			spread(1, 1, 1.0/16)
+		}
+	}
+	return out
+}
+
+// RenderImage draws any image as text with the glyphs of the options, half blocks by default,
+// the way text modes draw codes, for logos, other barcodes or pixel art.
+// Pixels darker than half brightness, or WithThreshold, get ink, or the lighter ones with
+// WithInverted, which suits light text on a dark terminal. WithDither draws greys as patterns.
+// There is no border or quiet zone.
+func RenderImage(img image.Image, opts ...Option) (string, error) {
+	if img == nil {
+		return "", ErrCodeNil
+	}
+	var q Encoder
+	q.threshold = 0.5
+	for _, opt := range opts {
+		opt(&q)
+	}
+	rc := &lightMode
+	if q.inverted {
+		rc = &darkMode
+	}
+	g := q.glyphs.set()
+	m := monochrome(img, q.threshold, q.dither)
+	var b strings.Builder
+	for y := 0; y < g.rows(m); y++ {
+		b.WriteString(g.row(rc, m, y) + "\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,102 @@
+package qrstr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"image/png"
+	"io"
+)
+
+// reportPage is the page of WriteReport. Clicking a column heading sorts the rows by it.
+var reportPage = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style{{with .Nonce}} nonce="{{.}}"{{end}}>
+body{font-family:sans-serif;margin:1em;}
+table{border-collapse:collapse;}
+th,td{border:1px solid #ccc;padding:.4em;text-align:left;vertical-align:top;}
+th{cursor:pointer;background:#eee;user-select:none;}
+td.payload{font-family:monospace;word-break:break-all;max-width:30em;}
+img{image-rendering:pixelated;display:block;}
+@media print{th{cursor:auto;}tr{break-inside:avoid;page-break-inside:avoid;}}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead><tr><th>#</th><th>Code</th><th>Caption</th><th>Payload</th><th>File</th><th>Version</th><th>ECL</th><th>Size</th><th>Fingerprint</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Index}}</td><td><img src="{{.Image}}" width="{{.Width}}" height="{{.Height}}" alt="{{or .Caption "QR code"}}"></td><td>{{.Caption}}</td><td class="payload">{{.Payload}}</td><td>{{.File}}</td><td>{{.Version}}</td><td>{{.ErrorCorrection}}</td><td>{{.Size}}</td><td title="{{.Fingerprint}}"><code>{{printf "%.16s" .Fingerprint}}</code></td></tr>
+{{- end}}
+</tbody>
+</table>
+<script{{with .Nonce}} nonce="{{.}}"{{end}}>
+document.querySelectorAll("th").forEach(function(th, i) {
+	var asc = true;
+	th.addEventListener("click", function() {
+		var body = th.closest("table").tBodies[0];
+		var rows = Array.from(body.rows);
+		rows.sort(function(a, b) {
+			var x = a.cells[i].textContent, y = b.cells[i].textContent;
+			var n = x - y;
+			return (isNaN(n) ? x.localeCompare(y) : n) * (asc ? 1 : -1);
+		});
+		asc = !asc;
+		rows.forEach(function(r) { body.appendChild(r); });
+	});
+});
+</script>
+</body>
+</html>
+`))
+
+// reportRow is one row of the page of WriteReport.
+type reportRow struct {
+	ManifestEntry
+	Image         template.URL
+	Width, Height int
+}
+
+// WriteReport writes one HTML page listing every item of a batch run with a picture of its
+// code, its caption, payload and the metadata of Manifest, in a table that sorts by any column,
+// for checking a run before it is printed. Items without a Result are encoded first, like
+// WriteBatch does, and an item that fails to encode stops the report.
+func (q *Encoder) WriteReport(w io.Writer, items []BatchItem, title string) error {
+	var seen batchCache
+	for i, v := range items {
+		if v.Result != nil {
+			continue
+		}
+		r, err := seen.encode(q, v)
+		if err != nil {
+			return err
+		}
+		items[i].Result = r
+	}
+	if title == "" {
+		title = "QR codes"
+	}
+	rows := make([]reportRow, len(items))
+	var b bytes.Buffer
+	for i, v := range Manifest(items) {
+		if err := q.checkOverlay(items[i].Result.code); err != nil {
+			return err
+		}
+		img := scaleImage(q.image(&items[i].Result.code), 4)
+		b.Reset()
+		if err := png.Encode(&b, img); err != nil {
+			return err
+		}
+		rows[i] = reportRow{v, template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(b.Bytes())), img.Bounds().Dx(), img.Bounds().Dy()}
+	}
+	return reportPage.Execute(w, struct {
+		Title string
+		Nonce string
+		Rows  []reportRow
+	}{title, q.nonce, rows})
+}
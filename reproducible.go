@@ -0,0 +1,30 @@
+package qrstr
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// WithReproducible makes output byte identical across runs and platforms for the same input,
+// for reproducible build pipelines. The qr mask and version are always picked the same way,
+// lowest penalty first and then lowest number, so this covers the rest: timestamps in SAUCE
+// records and archives come from the SOURCE_DATE_EPOCH environment variable, or are
+// 1980-01-01 UTC without it, and email boundaries are derived from the content.
+func WithReproducible() Option {
+	return func(q *Encoder) {
+		q.reproducible = true
+	}
+}
+
+// now returns the time to stamp output with.
+func (q *Encoder) now() time.Time {
+	if !q.reproducible {
+		return time.Now()
+	}
+	if v, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(v, 0).UTC()
+	}
+	// the earliest time zip archives can hold
+	return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,168 @@
+package qrstr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"strings"
+)
+
+// Result is an encoded code with the metadata of how it was made.
+type Result struct {
+	// Output is what Encode returns.
+	Output          string
+	Mode            EncoderType
+	ErrorCorrection ErrorCorrectionLevel
+	// Size is the width of the code in modules, without the quiet zone.
+	Size int
+	// Version is the qr version from 1 to 40, which sets the size.
+	Version int
+	// Inverted is true if dark modules are drawn light, see WithInverted.
+	Inverted bool
+	// Warnings are the findings of Lint for the encoder.
+	Warnings []Warning
+	// ID is the id of the svg or div of SVG and HTML modes, see WithIDPrefix, empty in other modes.
+	ID string
+
+	code      image.Image
+	config    Config
+	headers   []string
+	sensitive bool
+	enc       *Encoder
+}
+
+// EncodeResult encodes data like Encode and returns the output with its metadata.
+func (q *Encoder) EncodeResult(data string, headers ...string) (*Result, error) {
+	if q.strFunc == nil {
+		return nil, ErrCodeNil
+	}
+	var code image.Image
+	var shown []string
+	s, err := q.chain(func(data string, headers []string) (string, error) {
+		var err error
+		if code, err = q.code(data); err != nil {
+			return "", err
+		}
+		shown = q.prepare(code, headers)
+		return q.draw(&code, &shown)
+	})(data, headers)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		// a middleware answered without rendering, describe the payload it was given
+		if code, err = q.code(data); err != nil {
+			return nil, err
+		}
+		shown = q.prepare(code, headers)
+	}
+	size := code.Bounds().Dx()
+	id := ""
+	switch q.mode {
+	case SVGMode, HTMLFragmentMode:
+		id = q.codeID(code, nil)
+	case HTMLMode, HTMLGridMode, HTMLImageMode, HTMLSVGMode, HTMLCanvasMode, HTMLTableMode:
+		id = q.codeID(code, shown)
+	}
+	return &Result{
+		Output:          s,
+		Mode:            q.mode,
+		ErrorCorrection: q.errCorr,
+		Size:            size,
+		Version:         versionOf(size),
+		Inverted:        q.inverted && q.rc == nil,
+		Warnings:        q.Lint(),
+		ID:              id,
+		code:            code,
+		config:          q.DebugConfig(),
+		headers:         shown,
+		sensitive:       q.sensitive,
+		enc:             q,
+	}, nil
+}
+
+// Snapshot returns a stable description of the result for golden file tests: every setting
+// DebugConfig reports, the headers and a hash of the module matrix. It changes when the code
+// or those settings do, not when the layout of the output changes for other reasons, such as
+// the text of SAUCE records, WithDither or middleware that rewrites the output.
+func (r *Result) Snapshot() string {
+	sum := matrixHash(r.code)
+	var b strings.Builder
+	b.WriteString("qrstr snapshot 2\n")
+	fmt.Fprintf(&b, "%s\n", r.config)
+	fmt.Fprintf(&b, "version=%d size=%d\n", r.Version, r.Size)
+	if l := label(&r.code); l != "" {
+		fmt.Fprintf(&b, "short=%q\n", l)
+	}
+	for _, v := range r.headers {
+		fmt.Fprintf(&b, "header=%q\n", v)
+	}
+	fmt.Fprintf(&b, "matrix=sha256:%s\n", hex.EncodeToString(sum[:]))
+	return b.String()
+}
+
+// Fingerprint returns a hex sha256 hash of the mode and Output. Results with the same
+// fingerprint render to the same bytes, so one rendering can stand in for the other.
+func (r *Result) Fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", r.Mode)
+	h.Write([]byte(r.Output))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Dedupe finds results with the same fingerprint in a batch job.
+// The zero value is ready to use.
+type Dedupe struct {
+	seen map[string]*Result
+}
+
+// Add returns the first result added with the same fingerprint as r and true,
+// or r and false if there was none.
+func (d *Dedupe) Add(r *Result) (*Result, bool) {
+	f := r.Fingerprint()
+	if v, ok := d.seen[f]; ok {
+		return v, true
+	}
+	if d.seen == nil {
+		d.seen = make(map[string]*Result)
+	}
+	d.seen[f] = r
+	return r, false
+}
+
+// Line is one line of text output.
+type Line struct {
+	// Text is the line without colour escapes or line ending.
+	Text string
+	// Styled is the line as it is in the output, escapes included, without its line ending.
+	Styled string
+	// Width is the number of columns Text takes in a terminal.
+	Width int
+}
+
+// Lines splits the output of text modes into lines, so they can be placed or recoloured one by one.
+// ANSIMode and CP437Mode lines are converted from CP437, ANSIMode leaves out the SAUCE record.
+// It returns nil for HTML and SVG modes.
+func (r *Result) Lines() []Line {
+	out := r.Output
+	if eol := r.config.LineEnding; eol != "" {
+		out = strings.ReplaceAll(out, eol, "\n")
+	}
+	switch r.Mode {
+	case TextDarkMode, TextLightMode, TerminalMode, BrailleMode:
+	case ANSIMode, CP437Mode:
+		if i := strings.IndexByte(out, 0x1a); i >= 0 {
+			out = out[:i]
+		}
+		out = fromCP437(strings.ReplaceAll(out, "\r\n", "\n"))
+	default:
+		return nil
+	}
+	var lines []Line
+	for _, l := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		t := Sanitize(l, "")
+		lines = append(lines, Line{t, l, DisplayWidth(t)})
+	}
+	return lines
+}
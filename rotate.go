@@ -0,0 +1,60 @@
+package qrstr
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidInterval is the error of the only frame Rotate sends for an interval that is not positive.
+var ErrInvalidInterval = fmt.Errorf("rotate interval must be positive")
+
+// Frame is one code of a rotating display.
+type Frame struct {
+	// Payload is the payload the generator returned.
+	Payload string
+	// Output is the code rendered by the encoder.
+	Output string
+	// Err is set if the generator or the encoder failed, the display keeps rotating.
+	Err error
+	// At is when the frame was made, the next one follows an interval later.
+	At time.Time
+}
+
+// Rotate calls gen every interval, starting right away, and sends each payload rendered with
+// headers on the returned channel, for kiosks showing rotating check-in codes, pair it with
+// NewToken. Frames are not queued: if the receiver falls behind, the next one waits for it.
+// The channel is closed once ctx is done. If interval is not positive, the channel holds one
+// frame with ErrInvalidInterval and is closed.
+func (q *Encoder) Rotate(ctx context.Context, interval time.Duration, gen func() (string, error), headers ...string) <-chan Frame {
+	if interval <= 0 {
+		ch := make(chan Frame, 1)
+		ch <- Frame{Err: ErrInvalidInterval, At: time.Now()}
+		close(ch)
+		return ch
+	}
+	ch := make(chan Frame)
+	go func() {
+		defer close(ch)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			f := Frame{At: time.Now()}
+			f.Payload, f.Err = gen()
+			if f.Err == nil {
+				f.Output, f.Err = q.Encode(f.Payload, headers...)
+			}
+			select {
+			case ch <- f:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,20 @@
+package qrstr
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// ErrNotHTML is returned by EncodeTemplateHTML for modes whose output is not HTML or SVG.
+var ErrNotHTML = fmt.Errorf("mode does not output HTML")
+
+// EncodeTemplateHTML encodes data like Encode and returns it as template.HTML, to be put in
+// html/template templates as it is. Headers are always escaped, even with WithRawHTMLHeaders,
+// so untrusted headers can't inject markup.
+func (q *Encoder) EncodeTemplateHTML(data string, headers ...string) (template.HTML, error) {
+	if q.mode != SVGMode && q.mode.ext() != ".html" {
+		return "", ErrNotHTML
+	}
+	s, err := q.chain(q.renderHTML(htmlFlags{escape: true}))(data, headers)
+	return template.HTML(s), err
+}
@@ -0,0 +1,115 @@
+package qrstr
+
+import (
+	"image"
+	"strings"
+	"unicode/utf8"
+)
+
+// invisible reports whether r is a zero width or bidi control character that can hide or
+// reorder text. The zero width joiner is kept so emoji sequences stay intact.
+func invisible(r rune) bool {
+	switch {
+	case r == 0x00ad, r == 0x034f, r == 0x061c, r == 0x180e, r == 0xfeff:
+		return true
+	case r >= 0x200b && r <= 0x200f && r != 0x200d:
+		return true
+	case r >= 0x202a && r <= 0x202e, r >= 0x2060 && r <= 0x2069:
+		return true
+	}
+	return false
+}
+
+// Sanitize removes terminal escape sequences, control characters and zero width or bidi
+// control characters from s, putting repl in place of each one.
+// Untrusted text passed through it cannot change terminal colours or move the cursor.
+func Sanitize(s, repl string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == 0x1b || r == 0x9b || r == 0x9d || r == 0x90 || r == 0x98 || r == 0x9e || r == 0x9f:
+			n = escapeLen(s[i:])
+		case r < 0x20, r >= 0x7f && r < 0xa0, r == utf8.RuneError && n == 1, invisible(r):
+		default:
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		b.WriteString(repl)
+		i += n
+	}
+	return b.String()
+}
+
+// escapeLen returns the length in bytes of the escape sequence at the start of s.
+// Unterminated sequences run to the end of s.
+func escapeLen(s string) int {
+	r, n := utf8.DecodeRuneInString(s)
+	intro := r
+	if r == 0x1b {
+		if len(s) == 1 {
+			return 1
+		}
+		switch s[1] {
+		case '[':
+			intro = 0x9b
+		case ']':
+			intro = 0x9d
+		case 'P', 'X', '^', '_':
+			intro = 0x90
+		default:
+			// two character escapes, like ESC c or ESC 7
+			_, m := utf8.DecodeRuneInString(s[1:])
+			return 1 + m
+		}
+		n = 2
+	}
+	if intro == 0x9b {
+		// CSI: parameters and intermediates then one final byte from @ to ~
+		for i := n; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x3f && s[i] < 0x40 {
+				return i
+			}
+		}
+		return len(s)
+	}
+	// OSC, DCS, SOS, PM and APC run to BEL or the string terminator
+	for i := n; i < len(s); i++ {
+		switch {
+		case s[i] == 0x07:
+			return i + 1
+		case s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\':
+			return i + 2
+		case strings.HasPrefix(s[i:], "\u009c"):
+			return i + 2
+		}
+	}
+	return len(s)
+}
+
+// WithSanitize runs every header through Sanitize before rendering, replacing anything
+// removed with repl, so untrusted headers can't smuggle escape sequences into the output.
+func WithSanitize(repl string) Option {
+	return func(q *Encoder) {
+		q.sanitize = &repl
+	}
+}
+
+// prepare applies the header settings of the encoder to the headers of code.
+func (q *Encoder) prepare(code image.Image, headers []string) []string {
+	if q.templates && len(headers) > 0 {
+		headers = q.expand(code, headers)
+	}
+	if q.sanitize == nil || len(headers) == 0 {
+		return headers
+	}
+	clean := make([]string, len(headers))
+	for i, v := range headers {
+		clean[i] = Sanitize(v, *q.sanitize)
+	}
+	return clean
+}
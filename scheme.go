@@ -0,0 +1,64 @@
+package qrstr
+
+import (
+	"slices"
+	"sync"
+)
+
+// Scheme holds the characters text modes draw half blocks with, for a cell whose top and bottom
+// modules are both light, the top one dark, the bottom one dark, and both dark.
+// Other glyphs draw dark modules with ink if the first character is a space, light ones otherwise.
+type Scheme [4]rune
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]Scheme{
+		"light": Scheme(lightMode),
+		"dark":  Scheme(darkMode),
+		// ink for light modules, so the code and its quiet zone are the brightest thing on a dark terminal
+		"high-contrast": Scheme(darkMode),
+		// the characters of CP437Mode, which code page 437 has, for text converted to it later
+		"cp437": Scheme(darkMode),
+	}
+)
+
+// RegisterScheme adds a scheme for LookupScheme under name, replacing any scheme of that name.
+// The light and dark schemes are those of TextLightMode and TextDarkMode, high-contrast draws
+// light modules in ink like dark does and cp437 is the scheme of CP437Mode.
+func RegisterScheme(name string, s Scheme) {
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	schemes[name] = s
+}
+
+// LookupScheme returns the scheme registered under name.
+func LookupScheme(name string) (Scheme, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	s, ok := schemes[name]
+	return s, ok
+}
+
+// Schemes returns the names of the registered schemes in order.
+func Schemes() []string {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	names := make([]string, 0, len(schemes))
+	for k := range schemes {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WithScheme sets the characters of text modes and TerminalMode, in place of those of the mode.
+// Other modes ignore it.
+func WithScheme(s Scheme) Option {
+	return func(q *Encoder) {
+		if q.rc == nil {
+			return
+		}
+		rc := runeCol(s[:])
+		q.rc = &rc
+	}
+}
@@ -0,0 +1,40 @@
+package qrstr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// WithClassPrefix names the classes of HTML codes prefix, prefix-sr, prefix-grid and so on
+// instead of qr, so differently styled encoders on one page don't restyle each other's codes.
+// An empty prefix derives one from the colours and layout of the encoder, which is the same
+// for encoders that look the same. The prefix must be a valid CSS class name.
+func WithClassPrefix(prefix string) Option {
+	return func(q *Encoder) {
+		q.classPrefix = &prefix
+	}
+}
+
+// prefix returns the class name of HTML codes that the others start with.
+func (q *Encoder) prefix() string {
+	switch {
+	case q.classPrefix == nil:
+		return "qr"
+	case *q.classPrefix != "":
+		return *q.classPrefix
+	}
+	fg, bg := q.colors()
+	sum := sha256.Sum256([]byte(fmt.Sprint(q.mode, fg, bg, q.align, q.darkMode)))
+	return "qr" + hex.EncodeToString(sum[:4])
+}
+
+// scope renames the classes of css, written with the default class names, to those of prefix.
+func (q *Encoder) scope(css string) string {
+	p := q.prefix()
+	if p == "qr" {
+		return css
+	}
+	return strings.ReplaceAll(css, ".qr", "."+p)
+}
@@ -0,0 +1,81 @@
+package qrstr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Series describes a run of serial numbered codes, such as asset tags.
+type Series struct {
+	// Prefix is put before every serial number.
+	Prefix string
+	// Start is the first counter value.
+	Start int
+	// Count is the number of codes in the series.
+	Count int
+	// Digits zero pads the counter to this width.
+	Digits int
+	// ULID uses a ULID for each serial instead of the counter.
+	ULID bool
+	// Caption is a text/template for the header of each code, it can use
+	// {{.Index}} for the position in the series from 1 and {{.Payload}}.
+	// The payload itself is used if it is empty.
+	Caption string
+}
+
+// Items returns a batch item for every code of the series, ready for EncodeBatch.
+func (s Series) Items() ([]BatchItem, error) {
+	if s.Count < 1 {
+		return nil, fmt.Errorf("invalid series count: %d", s.Count)
+	}
+	caption := s.Caption
+	if caption == "" {
+		caption = "{{.Payload}}"
+	}
+	t, err := template.New("caption").Parse(caption)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	if s.ULID {
+		if ids, err = newULIDs(s.Count); err != nil {
+			return nil, err
+		}
+	}
+	items := make([]BatchItem, s.Count)
+	var b strings.Builder
+	for i := range items {
+		if s.ULID {
+			items[i].Payload = s.Prefix + ids[i]
+		} else {
+			items[i].Payload = fmt.Sprintf("%s%0*d", s.Prefix, s.Digits, s.Start+i)
+		}
+		b.Reset()
+		if err = t.Execute(&b, struct {
+			Index   int
+			Payload string
+		}{i + 1, items[i].Payload}); err != nil {
+			return nil, err
+		}
+		items[i].Headers = []string{b.String()}
+	}
+	return items, nil
+}
+
+// EncodeSeries renders every code of the series and writes a CSV manifest of the run to
+// manifest, if it is not nil.
+func (q *Encoder) EncodeSeries(s Series, manifest io.Writer) ([]BatchItem, error) {
+	items, err := s.Items()
+	if err != nil {
+		return nil, err
+	}
+	if err = q.EncodeBatch(items); err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		err = WriteManifest(manifest, items)
+	}
+	return items, err
+}
@@ -0,0 +1,90 @@
+package qrstr
+
+import (
+	"bytes"
+	"context"
+)
+
+// GenerateRequest asks Generate for a code. It mirrors the message of proto/qrstr.proto,
+// so gRPC and other RPC servers can share one implementation.
+type GenerateRequest struct {
+	Payload string   `json:"payload"`
+	Headers []string `json:"headers,omitempty"`
+	// Mode is a name of ParseEncoderType or png, svg by default.
+	Mode string `json:"mode,omitempty"`
+	// ErrorCorrection is L, M, Q or H, M by default.
+	ErrorCorrection string `json:"error_correction,omitempty"`
+	// Scale is the pixels per module of png and the terminal image modes, from 1 to 32 like
+	// Handler allows, larger values are clamped. 0 keeps the default of the mode.
+	Scale int `json:"scale,omitempty"`
+}
+
+// GenerateResponse is a code made by Generate, see GenerateRequest.
+type GenerateResponse struct {
+	// Output is bytes as some modes, like ansi and png, are not UTF-8.
+	Output      []byte `json:"output"`
+	ContentType string `json:"content_type"`
+	Version     int    `json:"version"`
+	// Size is the width of the code in modules, without the quiet zone.
+	Size        int    `json:"size"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Generate encodes a request with a new encoder for its mode and error correction level,
+// and any options after those of the request. The terminal mode keeps its colours.
+func Generate(ctx context.Context, req *GenerateRequest, opts ...Option) (*GenerateResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	mode := pngMode
+	if req.Mode == "" {
+		mode = SVGMode
+	} else if req.Mode != "png" {
+		var err error
+		if mode, err = ParseEncoderType(req.Mode); err != nil {
+			return nil, err
+		}
+	}
+	ecl := ErrorCorrection15Percent
+	if req.ErrorCorrection != "" {
+		var err error
+		if ecl, err = ParseErrorCorrectionLevel(req.ErrorCorrection); err != nil {
+			return nil, err
+		}
+	}
+	t := mode
+	if mode == pngMode {
+		t = TextLightMode
+	}
+	// the limit of Handler, so a client can't ask for an image that exhausts memory
+	scale := req.Scale
+	if scale != 0 {
+		scale = min(max(scale, 1), 32)
+	}
+	q, err := NewEncoder(t, ecl, append([]Option{WithForceColor(), WithScale(scale)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	r, err := q.EncodeResult(req.Payload, req.Headers...)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GenerateResponse{
+		Output:      []byte(r.Output),
+		ContentType: mode.ContentType(),
+		Version:     r.Version,
+		Size:        r.Size,
+		Fingerprint: r.Fingerprint(),
+	}
+	if mode == pngMode {
+		if len(req.Headers) > 0 {
+			return nil, ErrHeadersNotSupported
+		}
+		var b bytes.Buffer
+		if err := q.EncodePNG(&b, req.Payload); err != nil {
+			return nil, err
+		}
+		resp.Output = b.Bytes()
+	}
+	return resp, nil
+}
@@ -0,0 +1,169 @@
+package qrstr
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gfExp and gfLog are the exp and log tables for GF(2^8) with the AES polynomial.
+var gfExp [510]byte
+var gfLog [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i], gfExp[i+255] = x, x
+		gfLog[x] = byte(i)
+		// multiply by the generator 3
+		hi := x & 0x80
+		x ^= x << 1
+		if hi != 0 {
+			x ^= 0x1b
+		}
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// shareSum is the length of the checksum added to the secret so bad combinations are detected.
+const shareSum = 4
+
+var ErrShareInvalid = fmt.Errorf("text is not a valid secret share")
+var ErrSharesMismatch = fmt.Errorf("shares do not recover the secret, they may be from different secrets or too few were given")
+
+// SplitSecret splits secret into n shares using Shamir's secret sharing, any k of which recover it.
+// Each share is text in the form SSS:<k>:<index>:<hex>, which fits the alphanumeric qr mode.
+// The scheme is plain GF(256) sharing and is not compatible with SLIP-0039 wallets.
+func SplitSecret(secret []byte, n, k int) ([]string, error) {
+	if k < 1 || n < k || n > 255 {
+		return nil, fmt.Errorf("invalid share count %d with threshold %d", n, k)
+	}
+	sum := sha256.Sum256(secret)
+	secret = append(bytes.Clone(secret), sum[:shareSum]...)
+	coef := make([]byte, len(secret)*(k-1))
+	if _, err := rand.Read(coef); err != nil {
+		return nil, err
+	}
+	shares := make([]string, n)
+	data := make([]byte, len(secret))
+	for x := 1; x <= n; x++ {
+		for i, s := range secret {
+			// Horner's method from the highest coefficient down to the secret byte.
+			var y byte
+			for j := k - 2; j >= 0; j-- {
+				y = gfMul(y^coef[i*(k-1)+j], byte(x))
+			}
+			data[i] = y ^ s
+		}
+		shares[x-1] = fmt.Sprintf("SSS:%d:%d:%X", k, x, data)
+	}
+	return shares, nil
+}
+
+// CombineShares recovers a secret from at least the threshold number of shares made by SplitSecret.
+// A share may be given more than once, but two different shares with the same index are
+// ErrSharesMismatch.
+func CombineShares(shares ...string) ([]byte, error) {
+	var xs []byte
+	var ys [][]byte
+	k := 0
+	for _, s := range shares {
+		f := strings.Split(strings.TrimSpace(s), ":")
+		if len(f) != 4 || f[0] != "SSS" {
+			return nil, ErrShareInvalid
+		}
+		t, err := strconv.Atoi(f[1])
+		if err != nil || t < 1 || (k != 0 && t != k) {
+			return nil, ErrShareInvalid
+		}
+		k = t
+		x, err := strconv.Atoi(f[2])
+		if err != nil || x < 1 || x > 255 {
+			return nil, ErrShareInvalid
+		}
+		y, err := hex.DecodeString(f[3])
+		if err != nil || len(y) <= shareSum || (len(ys) > 0 && len(y) != len(ys[0])) {
+			return nil, ErrShareInvalid
+		}
+		if i := bytes.IndexByte(xs, byte(x)); i >= 0 {
+			// the same share given twice is harmless, two shares with one number are not
+			if !bytes.Equal(ys[i], y) {
+				return nil, ErrSharesMismatch
+			}
+			continue
+		}
+		xs = append(xs, byte(x))
+		ys = append(ys, y)
+	}
+	if len(xs) < k || k == 0 {
+		return nil, ErrSharesMismatch
+	}
+	xs, ys = xs[:k], ys[:k]
+	secret := make([]byte, len(ys[0]))
+	for i, xi := range xs {
+		// Lagrange basis polynomial for share i evaluated at zero.
+		l := byte(1)
+		for j, xj := range xs {
+			if i != j {
+				l = gfMul(l, gfDiv(xj, xj^xi))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(ys[i][b], l)
+		}
+	}
+	n := len(secret) - shareSum
+	sum := sha256.Sum256(secret[:n])
+	if !bytes.Equal(sum[:shareSum], secret[n:]) {
+		return nil, ErrSharesMismatch
+	}
+	return secret[:n], nil
+}
+
+// EncodeShares splits secret, such as a seed mnemonic, into n shares any k of which recover it,
+// and renders each share as its own qr code labeled with its number.
+// If k is 0 the secret is split into n plain parts instead, all of which are needed.
+// Shares are recombined with CombineShares, plain parts with ParsePart and JoinParts.
+// Any headers are displayed above every code, before the share label.
+func (q *Encoder) EncodeShares(secret string, n, k int, headers ...string) ([]string, error) {
+	var payloads []string
+	var label string
+	if k == 0 {
+		for _, p := range SplitParts(secret, n) {
+			payloads = append(payloads, p.String())
+		}
+		label = "Part %d of %d, all needed"
+	} else {
+		var err error
+		if payloads, err = SplitSecret([]byte(secret), n, k); err != nil {
+			return nil, err
+		}
+		label = "Share %d of %d, " + strconv.Itoa(k) + " needed"
+	}
+	codes := make([]string, len(payloads))
+	for i, p := range payloads {
+		s, err := q.Encode(p, append(headers[:len(headers):len(headers)], fmt.Sprintf(label, i+1, len(payloads)))...)
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = s
+	}
+	return codes, nil
+}
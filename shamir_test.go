@@ -0,0 +1,74 @@
+package qrstr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGF256(t *testing.T) {
+	// the multiplication examples of FIPS 197
+	if got := gfMul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("gfMul(0x57, 0x83) = %#x, want 0xc1", got)
+	}
+	if got := gfMul(0x57, 0x13); got != 0xfe {
+		t.Errorf("gfMul(0x57, 0x13) = %#x, want 0xfe", got)
+	}
+	if got := gfDiv(0xc1, 0x83); got != 0x57 {
+		t.Errorf("gfDiv(0xc1, 0x83) = %#x, want 0x57", got)
+	}
+}
+
+func TestCombineSharesKnown(t *testing.T) {
+	// "hi" and the first 4 bytes of its sha256, shared with the polynomial s + x
+	shares := []string{"SSS:2:1:69688E424247", "SSS:2:2:6A6B8D414144", "SSS:2:3:6B6A8C404045"}
+	for _, pair := range [][]string{{shares[0], shares[1]}, {shares[2], shares[0]}, {shares[1], shares[2]}} {
+		got, err := CombineShares(pair...)
+		if err != nil {
+			t.Fatalf("CombineShares(%q): %v", pair, err)
+		}
+		if string(got) != "hi" {
+			t.Errorf("CombineShares(%q) = %q, want \"hi\"", pair, got)
+		}
+	}
+	if got, err := CombineShares("SSS:1:1:68698F434346"); err != nil || string(got) != "hi" {
+		t.Errorf("CombineShares of a threshold 1 share = %q, %v, want \"hi\"", got, err)
+	}
+}
+
+func TestSplitCombineShares(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	shares, err := SplitSecret(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range shares {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				got, err := CombineShares(shares[i], shares[j], shares[k])
+				if err != nil || !bytes.Equal(got, secret) {
+					t.Errorf("shares %d, %d and %d gave %q, %v", i+1, j+1, k+1, got, err)
+				}
+			}
+		}
+	}
+	if _, err := CombineShares(shares[0], shares[1]); !errors.Is(err, ErrSharesMismatch) {
+		t.Errorf("two of three shares: got %v, want ErrSharesMismatch", err)
+	}
+	if _, err := CombineShares(shares[0], shares[0], shares[1]); !errors.Is(err, ErrSharesMismatch) {
+		t.Errorf("a repeated share counted twice: got %v, want ErrSharesMismatch", err)
+	}
+	if got, err := CombineShares(shares[0], shares[0], shares[1], shares[2]); err != nil || !bytes.Equal(got, secret) {
+		t.Errorf("a repeated share among enough shares gave %q, %v", got, err)
+	}
+	other, err := SplitSecret(bytes.Repeat([]byte("x"), len(secret)), 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineShares(shares[0], other[0], shares[1], shares[2]); !errors.Is(err, ErrSharesMismatch) {
+		t.Errorf("two shares with index 1: got %v, want ErrSharesMismatch", err)
+	}
+	if _, err := CombineShares("SSS:3:1:zz"); !errors.Is(err, ErrShareInvalid) {
+		t.Errorf("bad hex: got %v, want ErrShareInvalid", err)
+	}
+}
@@ -0,0 +1,123 @@
+package qrstr
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"image"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ShortCode returns a short code derived from a hash of data, three letters and three digits
+// like ABC-123, for people to type in when they can't scan. Letters that look like digits are left out.
+func ShortCode(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	letters := crockford[10:]
+	b := make([]byte, 0, 7)
+	for i := 0; i < 3; i++ {
+		b = append(b, letters[int(sum[i])%len(letters)])
+	}
+	b = append(b, '-')
+	for i := 3; i < 6; i++ {
+		b = append(b, '0'+sum[i]%10)
+	}
+	return string(b)
+}
+
+// WithShortCode shows a short human readable code below the symbol in every mode, as a fallback
+// for people who can't scan it. fn returns the code for a payload, ShortCode is used if it is nil.
+// Images draw letters, digits and dashes, anything else is left blank.
+func WithShortCode(fn func(data string) string) Option {
+	return func(q *Encoder) {
+		if fn == nil {
+			fn = ShortCode
+		}
+		q.short = fn
+	}
+}
+
+// labeled is a code image carrying the short code to show below it, if any, and its payload.
+type labeled struct {
+	image.Image
+	label   string
+	payload string
+}
+
+// label returns the short code to show below code, or "" if there is none.
+func label(code *image.Image) string {
+	if l, ok := (*code).(labeled); ok {
+		return l.label
+	}
+	return ""
+}
+
+// labelLine returns line with s written over its middle, with a blank either side.
+func labelLine(line, s string) string {
+	r := []rune(line)
+	s = string(blank) + s + string(blank)
+	n := utf8.RuneCountInString(s)
+	if n > len(r) {
+		return s
+	}
+	i := (len(r) - n) / 2
+	return string(r[:i]) + s + string(r[i+n:])
+}
+
+// font3x5 draws the characters of short codes, three pixels wide and five tall.
+// Each row is three bits, the highest on the left.
+var font3x5 = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7}, 'A': {2, 5, 7, 5, 5}, 'B': {6, 5, 6, 5, 6},
+	'C': {3, 4, 4, 4, 3}, 'D': {6, 5, 5, 5, 6}, 'E': {7, 4, 6, 4, 7}, 'F': {7, 4, 6, 4, 4},
+	'G': {3, 4, 5, 5, 3}, 'H': {5, 5, 7, 5, 5}, 'I': {7, 2, 2, 2, 7}, 'J': {1, 1, 1, 5, 2},
+	'K': {5, 5, 6, 5, 5}, 'L': {4, 4, 4, 4, 7}, 'M': {5, 7, 7, 5, 5}, 'N': {6, 5, 5, 5, 5},
+	'O': {2, 5, 5, 5, 2}, 'P': {6, 5, 6, 4, 4}, 'Q': {2, 5, 5, 6, 3}, 'R': {6, 5, 6, 5, 5},
+	'S': {3, 4, 2, 1, 6}, 'T': {7, 2, 2, 2, 2}, 'U': {5, 5, 5, 5, 7}, 'V': {5, 5, 5, 5, 2},
+	'W': {5, 5, 7, 7, 5}, 'X': {5, 5, 2, 5, 5}, 'Y': {5, 5, 2, 2, 2}, 'Z': {7, 1, 2, 4, 7},
+	'-': {0, 0, 7, 0, 0},
+}
+
+// drawLabel returns img with s drawn below it in font3x5, one pixel per module,
+// widening it if s does not fit. img must be paletted with dark modules at index 1.
+func drawLabel(img *image.Paletted, s string) *image.Paletted {
+	s = strings.ToUpper(s)
+	n := utf8.RuneCountInString(s)
+	tw := 4*n - 1
+	b := img.Bounds()
+	w := b.Dx()
+	if tw+2 > w {
+		w = tw + 2
+	}
+	// the text goes under the quiet zone with a module of margin below
+	dst := image.NewPaletted(image.Rect(0, 0, w, b.Dy()+6), img.Palette)
+	ox := (w - b.Dx()) / 2
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.SetColorIndex(ox+x, y, img.ColorIndexAt(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	x0 := (w - tw) / 2
+	i := 0
+	for _, r := range s {
+		g := font3x5[unicode.ToUpper(r)]
+		for y, row := range g {
+			for x := 0; x < 3; x++ {
+				if row&(4>>x) != 0 {
+					dst.SetColorIndex(x0+4*i+x, b.Dy()+y, 1)
+				}
+			}
+		}
+		i++
+	}
+	return dst
+}
+
+// svgLabel returns an SVG text element with s centred under a code w modules wide
+// whose bottom edge is at y, in colour fg.
+func (q *Encoder) svgLabel(s string, w, y int, fg string) string {
+	return fmt.Sprintf(`<text x="%g" y="%d" font-family="monospace" font-size="3" text-anchor="middle"%s>%s</text>`,
+		float64(w)/2, y+3, q.svgPaint("fill", fg), html.EscapeString(s))
+}
@@ -0,0 +1,79 @@
+package qrstr
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Sink stores the files of a batch run, like a directory, an archive or an object store bucket.
+// Names are slash separated paths that stay inside the sink.
+type Sink interface {
+	Put(name string, r io.Reader) error
+}
+
+// DirSink is a Sink that writes files below the directory it names,
+// creating subdirectories as needed.
+type DirSink string
+
+func (d DirSink) Put(name string, r io.Reader) error {
+	p := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteBatch renders each item in turn and puts it in s.
+// The file names come from name, a text/template that can use {{.Index}} for the position
+// of the item from 1, {{.Payload}}, hashed WithSensitive, and {{.Ext}} for the extension of the mode, like .svg.
+// ArchiveName is used if name is empty. The File and Result of each item are set as it is
+// stored, so the run can be listed with WriteManifest afterwards. Items with the same payload
+// and headers are rendered once, each still gets its own file.
+func (q *Encoder) WriteBatch(s Sink, items []BatchItem, name string) error {
+	if name == "" {
+		name = ArchiveName
+	}
+	t, err := template.New("name").Parse(name)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	var seen batchCache
+	for i, v := range items {
+		b.Reset()
+		if err = t.Execute(&b, struct {
+			Index   int
+			Payload string
+			Ext     string
+		}{i + 1, q.Redacted(v.Payload), q.mode.ext()}); err != nil {
+			return err
+		}
+		// names that could escape the directory the files end up in are refused
+		if !fs.ValidPath(b.String()) || b.String() == "." {
+			return fmt.Errorf("invalid file name: %q", b.String())
+		}
+		r, err := seen.encode(q, v)
+		if err != nil {
+			return err
+		}
+		if err = s.Put(b.String(), strings.NewReader(q.file(q.mode, r.Output))); err != nil {
+			return err
+		}
+		items[i].File = b.String()
+		items[i].Result = r
+	}
+	return nil
+}
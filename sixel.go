@@ -0,0 +1,67 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// sixel draws the code as a DEC sixel image, with any headers as plain lines above it.
+func (q *Encoder) sixel(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	img := scaleImage(q.image(code), scale).(*image.Paletted)
+	var b strings.Builder
+	if headers != nil {
+		for _, v := range *headers {
+			b.WriteString(v + "\n")
+		}
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	// every pixel is painted in one of the two colours, the raster attributes give square pixels and the size
+	fmt.Fprintf(&b, "\033P0;1;0q\"1;1;%d;%d", w, h)
+	for i, c := range img.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+	for y := 0; y < h; y += 6 {
+		for i := range img.Palette {
+			if i > 0 {
+				b.WriteByte('$')
+			}
+			fmt.Fprintf(&b, "#%d", i)
+			run, last := 0, byte(0)
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&b, "!%d%c", run, last)
+				case run > 0:
+					b.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+			for x := 0; x < w; x++ {
+				bits := 0
+				for j := 0; j < 6 && y+j < h; j++ {
+					if int(img.ColorIndexAt(x, y+j)) == i {
+						bits |= 1 << j
+					}
+				}
+				c := byte(63 + bits)
+				if c != last {
+					flush()
+					run, last = 0, c
+				}
+				run++
+			}
+			flush()
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\033\\\n")
+	return b.String(), nil
+}
@@ -0,0 +1,56 @@
+package qrstr
+
+// EncodeLines renders data one line at a time, calling fn with each line without its newline,
+// so output can be fed to a printer or written out without holding the whole string.
+// Text modes use their own characters and TerminalMode adds its colours to each line, or around
+// them all with WithResetOnce, other modes use the light mode characters. It stops at the first error from fn.
+func (q *Encoder) EncodeLines(data string, fn func(line string) error, headers ...string) error {
+	code, err := q.code(data)
+	if err != nil {
+		return err
+	}
+	headers = q.prepare(code, headers)
+	if q.mode != TerminalMode || !q.escapes() {
+		return q.textLines(q.textRC(), &code, &headers, fn)
+	}
+	// each line is held back until the next, so the last one can reset the colours
+	var held string
+	n := 0
+	err = q.textLines(q.textRC(), &code, &headers, func(line string) error {
+		n++
+		if n > 1 {
+			if err := fn(q.termLine(held, n == 2, false)); err != nil {
+				return err
+			}
+		}
+		held = line
+		return nil
+	})
+	if err != nil || n == 0 {
+		return err
+	}
+	return fn(q.termLine(held, n == 1, true))
+}
+
+// EncodeRows calls fn with each row of pixels of the image EncodeImage would return,
+// true for dark modules, even if WithInverted draws them white.
+// The row is reused between calls, copy it to keep it.
+// It stops at the first error from fn.
+func (q *Encoder) EncodeRows(data string, fn func(row []bool) error) error {
+	img, err := q.EncodeImage(data)
+	if err != nil {
+		return err
+	}
+	p := img.(interface{ ColorIndexAt(x, y int) uint8 })
+	b := img.Bounds()
+	row := make([]bool, b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := range row {
+			row[x] = p.ColorIndexAt(b.Min.X+x, y) == 1
+		}
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
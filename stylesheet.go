@@ -0,0 +1,71 @@
+package qrstr
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// ErrNoStyleSheet is returned by HTMLBody for modes other than HTMLMode, HTMLSVGMode and HTMLGridMode.
+var ErrNoStyleSheet = fmt.Errorf("mode has no shared style sheet")
+
+// HTMLStyle returns a <style> element for the codes of HTMLBody, in the colours and settings of
+// the encoder, with the nonce of WithNonce if one is set. It styles codes of every version, so
+// a page needs one copy however many codes it shows.
+func (q *Encoder) HTMLStyle() string {
+	fg, bg := q.colors()
+	var b strings.Builder
+	fmt.Fprintf(&b, ".qr{%s}.qr-sr{%s}", fmt.Sprintf(htmlStyle, "", bg, fg), srOnly)
+	for n := 21; n <= 177; n += 4 {
+		fmt.Fprintf(&b, ".qr-%d{width: %dem;}", n+1, n+1)
+	}
+	if q.align != AlignStart {
+		fmt.Fprintf(&b, ".qr-%s{text-align: %s;}", q.align, q.align)
+	}
+	if q.mode == HTMLGridMode {
+		b.WriteString(q.gridCSS())
+	}
+	b.WriteString(q.svgRules())
+	if q.darkMode {
+		fmt.Fprintf(&b, darkStyle, fg, bg)
+	}
+	nonce := ""
+	if q.nonce != "" {
+		nonce = ` nonce="` + html.EscapeString(q.nonce) + `"`
+	}
+	return "<style" + nonce + ">" + q.scope(b.String()) + "</style>\n"
+}
+
+// HTMLBody encodes data like Encode, but without the <style> elements, leaving the styling
+// to one copy of HTMLStyle on the page. It works in HTMLMode, HTMLSVGMode and HTMLGridMode.
+func (q *Encoder) HTMLBody(data string, headers ...string) (string, error) {
+	if q.mode != HTMLMode && q.mode != HTMLSVGMode && q.mode != HTMLGridMode {
+		return "", ErrNoStyleSheet
+	}
+	return q.chain(q.renderHTML(htmlFlags{shared: true}))(data, headers)
+}
+
+// renderHTML returns a RenderFunc like render that draws the divs of HTMLMode, HTMLSVGMode
+// and HTMLGridMode with f, and is render in other modes.
+func (q *Encoder) renderHTML(f htmlFlags) RenderFunc {
+	if q.mode != HTMLMode && q.mode != HTMLSVGMode && q.mode != HTMLGridMode {
+		return q.render
+	}
+	return func(data string, headers []string) (string, error) {
+		code, err := q.code(data)
+		if err != nil {
+			return "", err
+		}
+		headers = q.prepare(code, headers)
+		var s string
+		if q.mode == HTMLGridMode {
+			s = q.gridCode(&code, &headers, f)
+		} else {
+			s = q.htmlCode(&code, &headers, f)
+		}
+		if q.document == nil || f.shared {
+			return s, nil
+		}
+		return q.wrap(s, code, headers), nil
+	}
+}
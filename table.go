@@ -0,0 +1,57 @@
+package qrstr
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"strings"
+)
+
+// htmlTable draws the code as a table with inline styles on every cell, a cell for each run of
+// modules of one colour in a row, for email clients that strip <style> elements and classes.
+func (q *Encoder) htmlTable(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	img := q.image(code).(*image.Paletted)
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	alt := q.altText(code, headers)
+	fg, bg := q.palette()
+	colors := [2]string{cssColor(bg), cssColor(fg)}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<table id="%s" role="img" aria-label="%s" width="%d" cellpadding="0" cellspacing="0" border="0" style="border-collapse:collapse;border-spacing:0;background:%s;">`,
+		q.codeID(*code, deref(headers)), html.EscapeString(alt), w*scale, colors[0])
+	b.WriteString("\n")
+	if headers != nil {
+		for _, v := range *headers {
+			fmt.Fprintf(&b, `<tr><td colspan="%d" style="padding:%dpx %dpx 0;font-family:monospace;color:%s;text-align:center;">%s</td></tr>`,
+				w, scale, scale, colors[1], html.EscapeString(v))
+			b.WriteString("\n")
+		}
+	}
+	for y := 0; y < h; y++ {
+		// font-size and line-height stop clients padding empty cells to the height of a line of text
+		fmt.Fprintf(&b, `<tr style="height:%dpx;">`, scale)
+		for x := 0; x < w; {
+			c := img.ColorIndexAt(x, y)
+			n := 1
+			for x+n < w && img.ColorIndexAt(x+n, y) == c {
+				n++
+			}
+			span := ""
+			if n > 1 {
+				span = fmt.Sprintf(` colspan="%d"`, n)
+			}
+			fmt.Fprintf(&b, `<td%s style="width:%dpx;height:%dpx;padding:0;font-size:0;line-height:0;background:%s;"></td>`,
+				span, n*scale, scale, colors[c])
+			x += n
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>")
+	return b.String(), nil
+}
@@ -0,0 +1,67 @@
+package qrstr
+
+import (
+	"encoding/hex"
+	"image"
+	"strings"
+	"text/template"
+)
+
+// HeaderData is what headers can refer to with WithHeaderTemplates, like {{.Version}}.
+type HeaderData struct {
+	// Version is the qr version from 1 to 40 and Size the width of the code in modules.
+	Version int
+	Size    int
+	// ErrorCorrection is the letter of the error correction level: L, M, Q or H.
+	ErrorCorrection string
+	// MatrixHash is a short hash of the modules, to tell printed codes apart. It is not the
+	// fingerprint of Result.Fingerprint and manifests, which covers the whole output.
+	MatrixHash string
+	// PartIndex and PartCount are the number of the part and how many there are when the
+	// payload is a part of SplitParts, as EncodeShares and EncodePGPPublicKey make, 0 otherwise.
+	PartIndex int
+	PartCount int
+}
+
+// WithHeaderTemplates expands headers as text/template templates of HeaderData before they
+// are shown, so they can say things like "Part {{.PartIndex}}/{{.PartCount}}" or
+// "v{{.Version}} {{.MatrixHash}}". Headers that are not valid templates are shown as they are.
+func WithHeaderTemplates() Option {
+	return func(q *Encoder) {
+		q.templates = true
+	}
+}
+
+// expand executes headers as templates of the data of code.
+func (q *Encoder) expand(code image.Image, headers []string) []string {
+	b := code.Bounds()
+	sum := matrixHash(code)
+	d := HeaderData{
+		Version:         versionOf(b.Dx()),
+		Size:            b.Dx(),
+		ErrorCorrection: q.errCorr.String(),
+		MatrixHash:      hex.EncodeToString(sum[:8]),
+	}
+	if l, ok := code.(labeled); ok {
+		if p, err := ParsePart(l.payload); err == nil {
+			d.PartIndex, d.PartCount = p.Index, p.Count
+		}
+	}
+	out := make([]string, len(headers))
+	var s strings.Builder
+	for i, v := range headers {
+		out[i] = v
+		if !strings.Contains(v, "{{") {
+			continue
+		}
+		t, err := template.New("header").Parse(v)
+		if err != nil {
+			continue
+		}
+		s.Reset()
+		if t.Execute(&s, d) == nil {
+			out[i] = s.String()
+		}
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+
+package qrstr
+
+import "os"
+
+// termCellAspect returns 0, the cell size is not known on this platform.
+func termCellAspect() float64 {
+	return 0
+}
+
+// termWidth returns 0, the terminal size is not known on this platform.
+func termWidth() int {
+	return 0
+}
+
+// isTerminal reports whether standard output is a character device, as consoles are
+// and pipes and files are not.
+func isTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
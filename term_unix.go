@@ -0,0 +1,53 @@
+//go:build linux || darwin
+
+package qrstr
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the terminal size reported by the TIOCGWINSZ ioctl.
+type winsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// getWinsize asks the terminal on standard output for its size, which fails if it is not a terminal.
+func getWinsize() (winsize, syscall.Errno) {
+	var ws winsize
+	_, _, e := syscall.Syscall(syscall.SYS_IOCTL, os.Stdout.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws)))
+	return ws, e
+}
+
+// termSize returns the size of the terminal on standard output.
+func termSize() (winsize, bool) {
+	ws, e := getWinsize()
+	return ws, e == 0 && ws.Row > 0 && ws.Col > 0
+}
+
+// termCellAspect returns the height to width ratio of the character cells of the terminal
+// on standard output, or 0 if the terminal does not report its size in pixels.
+func termCellAspect() float64 {
+	ws, ok := termSize()
+	if !ok || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return 0
+	}
+	return (float64(ws.Ypixel) / float64(ws.Row)) / (float64(ws.Xpixel) / float64(ws.Col))
+}
+
+// termWidth returns the number of columns of the terminal on standard output, or 0 if it is not a terminal.
+func termWidth() int {
+	ws, ok := termSize()
+	if !ok {
+		return 0
+	}
+	return int(ws.Col)
+}
+
+// isTerminal reports whether standard output is a terminal.
+func isTerminal() bool {
+	// a terminal may not know its size, like a pty nobody set one for
+	_, e := getWinsize()
+	return e == 0
+}
@@ -0,0 +1,53 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// texEscape escapes the characters LaTeX treats specially.
+var texEscape = strings.NewReplacer(
+	`\`, `\textbackslash{}`, `{`, `\{`, `}`, `\}`, `$`, `\$`, `&`, `\&`, `#`, `\#`,
+	`%`, `\%`, `_`, `\_`, `^`, `\textasciicircum{}`, `~`, `\textasciitilde{}`,
+)
+
+// tikz draws the code as a TikZ picture of filled rectangles, one unit per module and y counting
+// down, at the width of WithPhysicalSize. Headers are set above it in a centred node.
+func (q *Encoder) tikz(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	img := q.image(code).(*image.Paletted)
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	mm := q.mm
+	if mm <= 0 {
+		mm = 50
+	}
+	fg, bg := q.palette()
+	var b strings.Builder
+	fmt.Fprintf(&b, "\\begin{tikzpicture}[x=%.4fmm,y=-%.4fmm]\n", mm/float64(w), mm/float64(w))
+	if _, _, _, a := bg.RGBA(); a != 0 {
+		fmt.Fprintf(&b, "\\fill[fill=%s] (0,0) rectangle (%d,%d);\n", texColor(bg), w, h)
+	}
+	if headers != nil && len(*headers) > 0 {
+		lines := make([]string, len(*headers))
+		for i, v := range *headers {
+			lines[i] = texEscape.Replace(v)
+		}
+		fmt.Fprintf(&b, "\\node[anchor=south,align=center,font=\\ttfamily] at (%g,0) {%s};\n", float64(w)/2, strings.Join(lines, `\\`))
+	}
+	fmt.Fprintf(&b, "\\fill[fill=%s]", texColor(fg))
+	darkRuns(img, func(x, y, n int) {
+		fmt.Fprintf(&b, "\n  (%d,%d) rectangle ++(%d,1)", x, y, n)
+	})
+	b.WriteString(";\n\\end{tikzpicture}\n")
+	return b.String(), nil
+}
+
+// texColor returns c as an xcolor expression.
+func texColor(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("{rgb,255:red,%d;green,%d;blue,%d}", r>>8, g>>8, b>>8)
+}
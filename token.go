@@ -0,0 +1,63 @@
+package qrstr
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrTokenInvalid = fmt.Errorf("token is malformed or its signature does not match")
+var ErrTokenExpired = fmt.Errorf("token has expired")
+
+// tokenMAC returns the truncated HMAC-SHA256 of the signed part of a token.
+func tokenMAC(key []byte, signed string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(signed))
+	// 128 bits is plenty against forgery and keeps the code small
+	return m.Sum(nil)[:16]
+}
+
+// NewToken returns payload signed with key and valid for ttl, for short lived check-in codes.
+// The token is payload.expiry.mac, with the expiry in unix seconds in base 36 and the
+// HMAC-SHA256 of the rest in unpadded base64url. The payload may contain dots.
+func NewToken(key []byte, payload string, ttl time.Duration) string {
+	signed := payload + "." + strconv.FormatInt(time.Now().Add(ttl).Unix(), 36)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(tokenMAC(key, signed))
+}
+
+// VerifyToken checks the signature and expiry of a token from NewToken and returns its payload.
+// The signature is compared in constant time.
+func VerifyToken(key []byte, token string) (string, error) {
+	return VerifyTokenAt(key, token, time.Now())
+}
+
+// VerifyTokenAt is VerifyToken with the expiry checked against t instead of the current time,
+// for checking tokens as of when they were scanned, or against a trusted clock.
+// The signature is checked before the expiry, so forged tokens never report ErrTokenExpired.
+func VerifyTokenAt(key []byte, token string, t time.Time) (string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return "", ErrTokenInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil || !hmac.Equal(mac, tokenMAC(key, token[:i])) {
+		return "", ErrTokenInvalid
+	}
+	signed := token[:i]
+	i = strings.LastIndexByte(signed, '.')
+	if i < 0 {
+		return "", ErrTokenInvalid
+	}
+	exp, err := strconv.ParseInt(signed[i+1:], 36, 64)
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+	if t.Unix() >= exp {
+		return "", ErrTokenExpired
+	}
+	return signed[:i], nil
+}
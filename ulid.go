@@ -0,0 +1,61 @@
+package qrstr
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// crockford is the base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulid formats a 48 bit millisecond time and 80 bits of entropy as a 26 character ULID.
+func ulid(t time.Time, entropy [10]byte) string {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	binary.BigEndian.PutUint16(id[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:], uint32(ms))
+	copy(id[6:], entropy[:])
+	hi := binary.BigEndian.Uint64(id[0:])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var s [26]byte
+	// 128 bits in 26 groups of 5 bits, the first group has only 3 bits
+	for i := 25; i >= 0; i-- {
+		s[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s[:])
+}
+
+// NewULID returns a new ULID for the current time with random entropy.
+func NewULID() (string, error) {
+	var e [10]byte
+	if _, err := rand.Read(e[:]); err != nil {
+		return "", err
+	}
+	return ulid(time.Now(), e), nil
+}
+
+// newULIDs returns n ULIDs that sort in the order they were made,
+// even when they share the same millisecond.
+func newULIDs(n int) ([]string, error) {
+	var e [10]byte
+	if _, err := rand.Read(e[:]); err != nil {
+		return nil, err
+	}
+	// leave room in the top bit so incrementing never wraps
+	e[0] &= 0x7f
+	t := time.Now()
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = ulid(t, e)
+		for j := len(e) - 1; j >= 0; j-- {
+			e[j]++
+			if e[j] != 0 {
+				break
+			}
+		}
+	}
+	return ids, nil
+}
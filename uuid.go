@@ -0,0 +1,64 @@
+package qrstr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+var ErrUUIDInvalid = fmt.Errorf("text is not a valid uuid")
+var ErrULIDInvalid = fmt.Errorf("text is not a valid ulid")
+
+// NewUUID returns a random version 4 UUID in upper case, which keeps qr codes
+// in the smaller alphanumeric mode.
+func NewUUID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u[:]), nil
+}
+
+func formatUUID(u []byte) string {
+	return strings.ToUpper(fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]))
+}
+
+// UUIDPayload checks that s is a UUID and returns it in the canonical hyphenated form in upper case,
+// which keeps qr codes in the smaller alphanumeric mode. Braces and a urn:uuid: prefix are accepted.
+func UUIDPayload(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 9 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return "", ErrUUIDInvalid
+		}
+		s = strings.ReplaceAll(s, "-", "")
+	}
+	u, err := hex.DecodeString(s)
+	if err != nil || len(u) != 16 {
+		return "", ErrUUIDInvalid
+	}
+	return formatUUID(u), nil
+}
+
+// ULIDPayload checks that s is a ULID and returns it in canonical upper case.
+// The letters I, L and O are read as 1, 1 and 0 as Crockford's base32 allows.
+func ULIDPayload(s string) (string, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) != 26 || s[0] > '7' {
+		return "", ErrULIDInvalid
+	}
+	s = strings.NewReplacer("I", "1", "L", "1", "O", "0").Replace(s)
+	for _, r := range s {
+		if !strings.ContainsRune(crockford, r) {
+			return "", ErrULIDInvalid
+		}
+	}
+	return s, nil
+}
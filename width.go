@@ -0,0 +1,94 @@
+package qrstr
+
+import "unicode"
+
+// DisplayWidth returns the number of terminal columns s takes: 2 for emoji and East Asian
+// wide characters, 0 for combining marks and the joiners and modifiers inside emoji sequences,
+// 1 for anything else. Flags, skin tones and ZWJ sequences count as the one emoji they show.
+func DisplayWidth(s string) int {
+	n := 0
+	graphemes(s, func(start, end, w int) {
+		n += w
+	})
+	return n
+}
+
+// graphemes calls fn with the byte range and width of each user-perceived character of s,
+// following the rules of Unicode text segmentation for combining marks and emoji.
+func graphemes(s string, fn func(start, end, w int)) {
+	start, w := -1, 0
+	var prev rune
+	ri := 0 // regional indicators in the current cluster
+	for i, r := range s {
+		join := start >= 0 && (extends(r) ||
+			prev == 0x200d && pictographic(r) ||
+			regional(r) && ri%2 == 1)
+		if !join {
+			if start >= 0 {
+				fn(start, i, w)
+			}
+			start, w, ri = i, runeWidth(r), 0
+		} else if r == 0xfe0f && pictographic(prev) {
+			// emoji presentation makes text style symbols like ❤ wide
+			w = 2
+		}
+		if regional(r) {
+			ri++
+			w = 2
+		}
+		prev = r
+	}
+	if start >= 0 {
+		fn(start, len(s), w)
+	}
+}
+
+// extends reports whether r joins the character before it: combining marks, joiners,
+// variation selectors, emoji modifiers and tags.
+func extends(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) || r == 0x200d ||
+		r >= 0xfe00 && r <= 0xfe0f || r >= 0x1f3fb && r <= 0x1f3ff || r >= 0xe0020 && r <= 0xe007f ||
+		r >= 0xe0100 && r <= 0xe01ef
+}
+
+// regional reports whether r is a regional indicator, two of which make a flag.
+func regional(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// pictographic reports whether r may start an emoji, roughly Extended_Pictographic.
+func pictographic(r rune) bool {
+	return r >= 0x1f000 && r <= 0x1faff || r >= 0x2190 && r <= 0x2bff || r == 0xa9 || r == 0xae ||
+		r == 0x203c || r == 0x2049 || r == 0x2122 || r == 0x2139
+}
+
+// wideSymbols are the emoji below U+1F000 shown wide without a variation selector.
+var wideSymbols = []rune{
+	0x231a, 0x231b, 0x23e9, 0x23ea, 0x23eb, 0x23ec, 0x23f0, 0x23f3, 0x25fd, 0x25fe, 0x2614, 0x2615,
+	0x267f, 0x2693, 0x26a1, 0x26aa, 0x26ab, 0x26bd, 0x26be, 0x26c4, 0x26c5, 0x26ce, 0x26d4, 0x26ea,
+	0x26f2, 0x26f3, 0x26f5, 0x26fa, 0x26fd, 0x2705, 0x270a, 0x270b, 0x2728, 0x274c, 0x274e, 0x2753,
+	0x2754, 0x2755, 0x2757, 0x2795, 0x2796, 0x2797, 0x27b0, 0x27bf, 0x2b1b, 0x2b1c, 0x2b50, 0x2b55,
+}
+
+// runeWidth returns the columns r takes on its own.
+func runeWidth(r rune) int {
+	switch {
+	case r < ' ' || r == 0x7f || extends(r) || r == 0x200b:
+		return 0
+	case r >= 0x1100 && r <= 0x115f, r >= 0x2e80 && r <= 0x303e, r >= 0x3041 && r <= 0x33ff,
+		r >= 0x3400 && r <= 0x4dbf, r >= 0x4e00 && r <= 0x9fff, r >= 0xa000 && r <= 0xa4cf,
+		r >= 0xac00 && r <= 0xd7a3, r >= 0xf900 && r <= 0xfaff, r >= 0xfe30 && r <= 0xfe4f,
+		r >= 0xff00 && r <= 0xff60, r >= 0xffe0 && r <= 0xffe6, r >= 0x20000 && r <= 0x3fffd,
+		r >= 0x1f300 && r <= 0x1f64f, r >= 0x1f680 && r <= 0x1f6ff, r >= 0x1f900 && r <= 0x1faff,
+		r == 0x1f004, r == 0x1f0cf, r == 0x1f18e, r >= 0x1f191 && r <= 0x1f19a, r >= 0x1f200 && r <= 0x1f251:
+		return 2
+	case r >= 0x2648 && r <= 0x2653:
+		return 2
+	}
+	for _, v := range wideSymbols {
+		if r == v {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,123 @@
+package qrstr
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WrapText wraps each string to lines of at most width columns, breaking at spaces where
+// it can and hyphenating words longer than a line. Strings that fit are returned as they are,
+// without trailing spaces. Spaces where a line breaks are dropped, as are the leading spaces of
+// strings that do not fit, so no line is left empty.
+// Chinese, Japanese and Korean text may break between any two characters, without a hyphen.
+// Widths are counted in terminal columns and emoji sequences are never split, see DisplayWidth.
+// The lines share their memory with the strings, except for hyphenated pieces.
+func WrapText(width int, s ...string) []string {
+	return WrapTextHyphen(width, "-", s...)
+}
+
+// WrapTextHyphen is WrapText with words longer than a line broken with hyphen instead of "-",
+// or without any mark if hyphen is empty.
+func WrapTextHyphen(width int, hyphen string, s ...string) []string {
+	hw := DisplayWidth(hyphen)
+	if width < hw+1 {
+		width = hw + 1
+	}
+	lines := make([]string, 0, len(s))
+	for _, l := range s {
+		lines = wrapLine(lines, l, width, hyphen, hw)
+	}
+	return lines
+}
+
+// wrapper wraps one string, fed one grapheme cluster at a time by graphemes.
+type wrapper struct {
+	l, hyphen string
+	width, hw int
+	lines     []string
+	ls        int  // byte the current line starts at, -1 while skipping the spaces before it
+	lw        int  // columns of the current line
+	brk       int  // last byte the line may break before, ls if there is none
+	cut       int  // end of the longest part of the line that leaves room for the hyphen, ls if none
+	prev      rune // first rune of the last cluster
+}
+
+// wrapLine appends the lines of l wrapped to width to lines.
+func wrapLine(lines []string, l string, width int, hyphen string, hw int) []string {
+	l = strings.TrimRight(l, " ")
+	if DisplayWidth(l) <= width {
+		return append(lines, l)
+	}
+	w := wrapper{l: l, hyphen: hyphen, width: width, hw: hw, lines: lines, ls: -1}
+	graphemes(l, w.add)
+	// l ends with a cluster that is not a space, so the last line has started
+	return append(w.lines, l[w.ls:])
+}
+
+// add adds the cluster from byte start to end of l, cw columns wide, to the current line,
+// and breaks the line before it if it does not fit.
+func (w *wrapper) add(start, end, cw int) {
+	r, _ := utf8.DecodeRuneInString(w.l[start:])
+	if w.ls < 0 {
+		if r == ' ' {
+			return
+		}
+		w.ls, w.lw, w.brk, w.cut = start, 0, start, start
+	} else if canBreak(w.prev, r) {
+		w.brk = start
+	}
+	w.prev = r
+	if start == w.ls || w.lw+cw <= w.width {
+		w.lw += cw
+		if w.lw+w.hw <= w.width {
+			w.cut = end
+		}
+		return
+	}
+	var from int
+	switch {
+	case r == ' ':
+		w.lines = append(w.lines, strings.TrimRight(w.l[w.ls:start], " "))
+		w.ls = -1
+		return
+	case w.brk > w.ls:
+		w.lines = append(w.lines, strings.TrimRight(w.l[w.ls:w.brk], " "))
+		from = w.brk
+	case w.cut > w.ls:
+		// no place to break, the word is cut with room left for the hyphen
+		w.lines = append(w.lines, w.l[w.ls:w.cut]+w.hyphen)
+		from = w.cut
+	default:
+		// too wide for a hyphen, cut without one
+		w.lines = append(w.lines, w.l[w.ls:start])
+		from = start
+	}
+	// the clusters carried over to the new line may still break between them
+	w.ls = -1
+	graphemes(w.l[from:end], func(s, e, cw int) {
+		w.add(from+s, from+e, cw)
+	})
+}
+
+// canBreak reports whether a line may break between a and b: after a space, or next to a
+// Chinese, Japanese or Korean character, but not before closing punctuation or after opening.
+func canBreak(a, b rune) bool {
+	if b == ' ' {
+		return false
+	}
+	if a == ' ' {
+		return true
+	}
+	if !cjk(a) && !cjk(b) {
+		return false
+	}
+	return !strings.ContainsRune("、。，．・：；？！）」』】〕〉》ー…", b) && !strings.ContainsRune("（「『【〔〈《", a)
+}
+
+// cjk reports whether r is a Chinese, Japanese or Korean character or full width form,
+// which text in those languages breaks between.
+func cjk(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		r >= 0x3000 && r <= 0x303f || r >= 0xff00 && r <= 0xffef
+}
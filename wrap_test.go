@@ -0,0 +1,60 @@
+package qrstr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		in    string
+		want  []string
+	}{
+		{"empty", 8, "", []string{""}},
+		{"fits", 8, "two word", []string{"two word"}},
+		{"exact width", 8, "exactly8", []string{"exactly8"}},
+		{"fits leading spaces", 8, "  indent", []string{"  indent"}},
+		{"trailing spaces", 8, "trailing   ", []string{"trailing"}},
+		{"leading spaces", 8, "  leading", []string{"leading"}},
+		{"only spaces", 8, "           ", []string{""}},
+		{"space at break", 8, "exactly8 next", []string{"exactly8", "next"}},
+		{"multiple spaces", 8, "one   two   three", []string{"one", "two", "three"}},
+		{"spaces past width", 8, "word            end", []string{"word", "end"}},
+		{"break at space", 10, "hello there world", []string{"hello", "there", "world"}},
+		{"hyphenate", 8, "abcdefghijkl", []string{"abcdefg-", "hijkl"}},
+		{"hyphenate after spaces", 8, "   abcdefghijkl", []string{"abcdefg-", "hijkl"}},
+		{"cjk", 4, "日本語です", []string{"日本", "語で", "す"}},
+		{"wide emoji kept whole", 3, "👍🏽👍🏽", []string{"👍🏽-", "👍🏽"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapText(tt.width, tt.in)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("WrapText(%d, %q) = %q, want %q", tt.width, tt.in, got, tt.want)
+			}
+			for _, l := range got {
+				if w := DisplayWidth(l); w > tt.width {
+					t.Errorf("line %q is %d columns wide, more than %d", l, w, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestWrapTextHyphen(t *testing.T) {
+	got := WrapTextHyphen(6, "", "abcdefghij")
+	if want := []string{"abcdef", "ghij"}; !slices.Equal(got, want) {
+		t.Errorf("WrapTextHyphen(6, \"\", ...) = %q, want %q", got, want)
+	}
+	// the letters carried over after a hyphen are wrapped again, not left too wide
+	got = WrapTextHyphen(3, "--", "béx。")
+	if want := []string{"b--", "é--", "x。"}; !slices.Equal(got, want) {
+		t.Errorf("WrapTextHyphen(3, \"--\", ...) = %q, want %q", got, want)
+	}
+	got = WrapText(8, "one", "two three four")
+	if want := []string{"one", "two", "three", "four"}; !slices.Equal(got, want) {
+		t.Errorf("WrapText of two strings = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package qrstr
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// zplFont is the height in dots of header lines in ZPL output, which uses the scalable font 0.
+const zplFont = 25
+
+// zpl draws the code as a ZPL II label of filled boxes, one for each run of dark modules,
+// with any headers as text fields above it.
+func (q *Encoder) zpl(rc *runeCol, code *image.Image, headers *[]string) (string, error) {
+	if code == nil {
+		return "", ErrCodeNil
+	}
+	scale := q.scale
+	if scale <= 0 {
+		scale = 4
+	}
+	img := q.image(code).(*image.Paletted)
+	var b strings.Builder
+	// UTF-8 text, with ^FH for the characters fields can't hold
+	b.WriteString("^XA\n^CI28\n")
+	top := 0
+	if headers != nil {
+		for _, v := range *headers {
+			fmt.Fprintf(&b, "^FO%d,%d^A0N,%d,%d^FH^FD%s^FS\n", scale*q.quiet, top, zplFont, zplFont, zplField(v))
+			top += zplFont * 6 / 5
+		}
+	}
+	darkRuns(img, func(x, y, n int) {
+		// a box as thick as it is tall is filled
+		fmt.Fprintf(&b, "^FO%d,%d^GB%d,%d,%d^FS\n", x*scale, top+y*scale, n*scale, scale, scale)
+	})
+	b.WriteString("^XZ\n")
+	return b.String(), nil
+}
+
+// zplField escapes s for a ^FH field, writing the bytes of ^, ~, _ and control characters as hex.
+func zplField(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '^' || c == '~' || c == '_' || c < ' ' {
+			fmt.Fprintf(&b, "_%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}